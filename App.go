@@ -1,6 +1,8 @@
 package bolo
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"html/template"
@@ -9,6 +11,8 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -16,20 +20,25 @@ import (
 	"github.com/Masterminds/sprig"
 	"github.com/go-bolo/bolo/acl"
 	"github.com/go-bolo/bolo/configuration"
+	"github.com/go-bolo/bolo/database"
+	"github.com/go-bolo/bolo/documents"
 	"github.com/go-bolo/bolo/helpers"
 	"github.com/go-bolo/bolo/http_client"
+	"github.com/go-bolo/bolo/i18n"
 	"github.com/go-bolo/bolo/logger"
+	"github.com/go-bolo/bolo/metrics"
+	"github.com/go-bolo/bolo/models"
 	"github.com/go-bolo/bolo/pagination"
+	"github.com/go-bolo/bolo/tracing"
 	"github.com/go-bolo/clock"
 	"github.com/go-bolo/query_parser_to_db"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
 	"github.com/gookit/event"
 	"github.com/labstack/echo/v4"
 	"github.com/microcosm-cc/bluemonday"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
-	"gorm.io/driver/mysql"
-	"gorm.io/driver/sqlite"
 	gorm_logger "gorm.io/gorm/logger"
 
 	"gorm.io/gorm"
@@ -50,7 +59,24 @@ type App interface {
 	GetRouter() *echo.Echo
 	SetRouterGroup(name, path string) *echo.Group
 	GetRouterGroup(name string) *echo.Group
+	// Get the name of the router group with the longest path that contains the request path
+	GetRouterGroupNameByPath(path string) string
+	// Override the CORS configuration of one router group, set it before start the server
+	SetRouterGroupCORS(name string, opts *CORSOptions) error
+	GetRouterGroupCORS(name string) *CORSOptions
+	SetRouterGroupRateLimit(name string, policy *RateLimitPolicy) error
+	GetRouterGroupRateLimit(name string) *RateLimitPolicy
+	// Wrap requests with unsafe methods of the router group in one database transaction, see RequestContext.DB
+	SetRouterGroupTransaction(name string, enabled bool) error
+	IsRouterGroupTransactionEnabled(name string) bool
+	SetRateLimitStore(store RateLimitStore) error
+	GetRateLimitStore() RateLimitStore
 	SetResource(name string, httpController HTTPController, routerGroup *echo.Group) error
+	GetResources() map[string]*HTTPResource
+	SetRoute(routerGroup *echo.Group, route *Route) error
+	GetRoutes() []*Route
+	// Get one route registered with SetRoute by method and full path, like c.Path()
+	GetRoute(method, path string) *Route
 	StartHTTPServer() error
 	NewRequestContext(opts *RequestContextOpts) *RequestContext
 	// Get default app theme
@@ -69,6 +95,12 @@ type App interface {
 	InitDatabase(name, engine string, isDefault bool) error
 	SetModel(name string, f interface{})
 	GetModel(name string) interface{}
+	// Get the name of one model registered with SetModel, empty if not registered
+	GetModelName(model interface{}) string
+	// Get the fields, columns, validation tags and relations of one model registered with SetModel
+	GetModelSchema(name string) *ModelSchema
+	// Get the schemas of all registered models, sorted by name
+	GetModelSchemas() []*ModelSchema
 
 	Can(permission string, userRoles []string) bool
 	SetRole(name string, role acl.Role) error
@@ -78,6 +110,19 @@ type App interface {
 	GetRolePermission(name string, permission string) bool
 
 	GetEvents() *event.Manager
+	// Validator of the request bodies, used by echo.Context.Validate
+	GetValidator() *validator.Validate
+	// Translator of the validation error messages
+	GetValidationTranslator() *ut.UniversalTranslator
+	// Message catalogs of the app and plugins, see RequestContext.T
+	GetI18n() *i18n.Catalog
+	// Metrics registry exposed in /metrics, plugins can register their own metrics
+	GetMetrics() *metrics.Registry
+	GetTracer() *tracing.Tracer
+	SetTracer(tracer *tracing.Tracer) error
+	// Add one dependency check to the /health/ready endpoint
+	AddHealthCheck(check *HealthCheck) error
+	GetHealthChecks() []*HealthCheck
 
 	GetConfiguration() configuration.ConfigurationInterface
 
@@ -85,7 +130,29 @@ type App interface {
 	GetSanitizer() *bluemonday.Policy
 	SetSanitizer(policy *bluemonday.Policy) error
 
+	// Secret used to sign cookies and tokens, from APP_SECRET configuration
+	GetSecret() []byte
+	SetUserLoader(loader UserLoader)
+	GetUserLoader() UserLoader
+	AddAuthenticationStrategy(strategy AuthenticationStrategy) error
+	GetAuthenticationStrategies() []AuthenticationStrategy
+	SetSessionStore(store SessionStore) error
+	GetSessionStore() SessionStore
+
 	GetDB() *gorm.DB
+	GetDBs() map[string]*gorm.DB
+	// Connection pool stats by connection name
+	GetDBStats() map[string]sql.DBStats
+	// Get one named connection, nil if the connection is not initialized
+	GetDBByName(name string) *gorm.DB
+	// Use the named connection in one model, see GetModelDB
+	SetModelDB(modelName, dbName string) error
+	// Get the connection bound to the model or the default connection
+	GetModelDB(modelName string) *gorm.DB
+	// Use the named connection in one plugin, see GetPluginDB
+	SetPluginDB(pluginName, dbName string) error
+	// Get the connection bound to the plugin or the default connection
+	GetPluginDB(pluginName string) *gorm.DB
 	SetDB(db *gorm.DB) error
 	Migrate() error
 
@@ -106,6 +173,14 @@ type AppStruct struct {
 
 	clock clock.Clock
 
+	metrics *metrics.Registry
+	tracer  *tracing.Tracer
+
+	healthChecks []*HealthCheck
+	// model and plugin names bound to named connections
+	modelDBs  map[string]string
+	pluginDBs map[string]string
+
 	Options *AppOptions
 
 	Events *event.Manager
@@ -119,11 +194,23 @@ type AppStruct struct {
 	Plugins []Pluginer
 
 	Models map[string]interface{}
+	// schemas of the registered models, nil for models that are not structs
+	modelSchemas map[string]*ModelSchema
 
 	router    *echo.Echo
 	Resources map[string]*HTTPResource
 
-	routerGroups map[string]*echo.Group
+	routerGroups     map[string]*echo.Group
+	routerGroupPaths map[string]string
+	routerGroupCORS  map[string]*CORSOptions
+	// rate limit policies by router group name
+	routerGroupRateLimits map[string]*RateLimitPolicy
+	rateLimitStore        RateLimitStore
+	// router groups with request transactions enabled
+	routerGroupTransactions map[string]bool
+	routes                  []*Route
+	// routes indexed by method and full path
+	routesIndex map[string]*Route
 
 	RolesString string
 	RolesList   map[string]*acl.Role
@@ -135,6 +222,15 @@ type AppStruct struct {
 	templateFunctions template.FuncMap
 
 	sanitizer *bluemonday.Policy
+
+	validator            *validator.Validate
+	validationTranslator *ut.UniversalTranslator
+	i18n                 *i18n.Catalog
+
+	secret                   []byte
+	userLoader               UserLoader
+	authenticationStrategies []AuthenticationStrategy
+	sessionStore             SessionStore
 }
 
 func (app *AppStruct) GetSanitizer() *bluemonday.Policy {
@@ -146,6 +242,53 @@ func (app *AppStruct) SetSanitizer(sanitizer *bluemonday.Policy) error {
 	return nil
 }
 
+func (app *AppStruct) GetValidator() *validator.Validate {
+	return app.validator
+}
+
+func (app *AppStruct) GetValidationTranslator() *ut.UniversalTranslator {
+	return app.validationTranslator
+}
+
+func (app *AppStruct) GetI18n() *i18n.Catalog {
+	return app.i18n
+}
+
+func (app *AppStruct) GetSecret() []byte {
+	return app.secret
+}
+
+func (app *AppStruct) SetUserLoader(loader UserLoader) {
+	app.userLoader = loader
+}
+
+func (app *AppStruct) GetUserLoader() UserLoader {
+	return app.userLoader
+}
+
+// AddAuthenticationStrategy - Add one authentication strategy, strategies run in the order they are added
+func (app *AppStruct) AddAuthenticationStrategy(strategy AuthenticationStrategy) error {
+	if strategy.GetName() == "" {
+		return errors.New("bolo.App.AddAuthenticationStrategy strategy name is required")
+	}
+
+	app.authenticationStrategies = append(app.authenticationStrategies, strategy)
+	return nil
+}
+
+func (app *AppStruct) GetAuthenticationStrategies() []AuthenticationStrategy {
+	return app.authenticationStrategies
+}
+
+func (app *AppStruct) SetSessionStore(store SessionStore) error {
+	app.sessionStore = store
+	return nil
+}
+
+func (app *AppStruct) GetSessionStore() SessionStore {
+	return app.sessionStore
+}
+
 func (r *AppStruct) RegisterPlugin(p Pluginer) {
 	if p.GetName() == "" {
 		panic("Plugin.RegisterPlugin Name should be returned from GetName method")
@@ -216,11 +359,12 @@ func (app *AppStruct) NewRequestContext(opts *RequestContextOpts) *RequestContex
 		Domain:      domain,
 		AppOrigin:   cfg.GetF("APP_ORIGIN", protocol+"://"+domain+":"+port),
 		// Title:               "",
-		Theme:  cfg.GetF("THEME", "site"),
-		Layout: "layouts/default",
-		ENV:    cfg.GetF("GO_ENV", "development"),
-		Query:  query_parser_to_db.NewQuery(50),
-		Pager:  pagination.NewPager(),
+		Theme:   cfg.GetF("THEME", "site"),
+		Layout:  "layouts/default",
+		ENV:     cfg.GetF("GO_ENV", "development"),
+		Query:   query_parser_to_db.NewQuery(50),
+		Pager:   pagination.NewPager(),
+		Session: NewSession(app.GetClock().Now()),
 	}
 
 	if ctx.echoContext == nil {
@@ -232,6 +376,7 @@ func (app *AppStruct) NewRequestContext(opts *RequestContextOpts) *RequestContex
 		return &ctx
 	}
 
+	ctx.RequestID, _ = ctx.Get(RequestIDKey).(string)
 	ctx.Pager.CurrentUrl = ctx.Request().URL.Path
 	ctx.Pager.Limit, _ = strconv.ParseInt(cfg.GetF("PAGER_LIMIT", "20"), 10, 64)
 
@@ -311,11 +456,38 @@ func (r *AppStruct) GetConfiguration() configuration.ConfigurationInterface {
 	return r.Configuration
 }
 
+func (r *AppStruct) GetMetrics() *metrics.Registry {
+	return r.metrics
+}
+
+func (r *AppStruct) GetTracer() *tracing.Tracer {
+	return r.tracer
+}
+
+func (r *AppStruct) SetTracer(tracer *tracing.Tracer) error {
+	r.tracer = tracer
+	return nil
+}
+
+func (r *AppStruct) AddHealthCheck(check *HealthCheck) error {
+	r.healthChecks = append(r.healthChecks, check)
+	return nil
+}
+
+func (r *AppStruct) GetHealthChecks() []*HealthCheck {
+	return r.healthChecks
+}
+
+func (r *AppStruct) GetDBs() map[string]*gorm.DB {
+	return r.DBs
+}
+
 func (r *AppStruct) GetDB() *gorm.DB {
 	return r.DB
 }
 func (r *AppStruct) SetDB(db *gorm.DB) error {
 	r.DB = db
+	r.DBs["default"] = db
 	return nil
 }
 
@@ -335,6 +507,11 @@ func (r *AppStruct) Bootstrap() error {
 		}
 	}
 
+	err = loadTranslations(r)
+	if err != nil {
+		return errors.Wrap(err, "App.Bootstrap Error on load translations")
+	}
+
 	r.Events.MustTrigger("configuration", event.M{"app": r})
 
 	err = r.InitDatabase("default", configuration.GetEnv("DB_ENGINE", "sqlite"), true)
@@ -342,6 +519,11 @@ func (r *AppStruct) Bootstrap() error {
 		return err
 	}
 
+	err = r.initNamedDatabases()
+	if err != nil {
+		return err
+	}
+
 	http_client.Init()
 
 	r.Events.MustTrigger("bindMiddlewares", event.M{"app": r})
@@ -379,6 +561,7 @@ func (r *AppStruct) StartHTTPServer() error {
 func (r *AppStruct) SetRouterGroup(name, path string) *echo.Group {
 	if r.routerGroups[name] == nil {
 		r.routerGroups[name] = r.router.Group(path)
+		r.routerGroupPaths[name] = path
 	}
 	return r.routerGroups[name]
 }
@@ -387,10 +570,68 @@ func (r *AppStruct) GetRouterGroup(name string) *echo.Group {
 	return r.routerGroups[name]
 }
 
+func (r *AppStruct) GetRouterGroupNameByPath(path string) string {
+	name := ""
+	groupPath := ""
+
+	for n, p := range r.routerGroupPaths {
+		if isPathInRouterGroup(path, p) && len(p) > len(groupPath) {
+			name = n
+			groupPath = p
+		}
+	}
+
+	return name
+}
+
+func (r *AppStruct) SetRouterGroupCORS(name string, opts *CORSOptions) error {
+	r.routerGroupCORS[name] = opts
+	return nil
+}
+
+func (r *AppStruct) GetRouterGroupCORS(name string) *CORSOptions {
+	return r.routerGroupCORS[name]
+}
+
+func (r *AppStruct) SetRouterGroupRateLimit(name string, policy *RateLimitPolicy) error {
+	if policy.Limit <= 0 || policy.Window <= 0 {
+		return errors.New("bolo.App.SetRouterGroupRateLimit policy limit and window are required")
+	}
+
+	if policy.Name == "" {
+		policy.Name = name
+	}
+
+	r.routerGroupRateLimits[name] = policy
+	return nil
+}
+
+func (r *AppStruct) GetRouterGroupRateLimit(name string) *RateLimitPolicy {
+	return r.routerGroupRateLimits[name]
+}
+
+func (r *AppStruct) SetRouterGroupTransaction(name string, enabled bool) error {
+	r.routerGroupTransactions[name] = enabled
+	return nil
+}
+
+func (r *AppStruct) IsRouterGroupTransactionEnabled(name string) bool {
+	return r.routerGroupTransactions[name]
+}
+
+func (r *AppStruct) SetRateLimitStore(store RateLimitStore) error {
+	r.rateLimitStore = store
+	return nil
+}
+
+func (r *AppStruct) GetRateLimitStore() RateLimitStore {
+	return r.rateLimitStore
+}
+
 // Set Resource CRUD.
 // Now we only supports HTTP Resources / Ex Rest
 func (r *AppStruct) SetResource(name string, httpController HTTPController, routerGroup *echo.Group) error {
-	routerGroup.GET("", httpController.Query)
+	route := routerGroup.GET("", httpController.Query)
 	routerGroup.GET("/count", httpController.Count)
 	routerGroup.POST("", httpController.Create)
 	routerGroup.GET("/:id", httpController.FindOne)
@@ -399,34 +640,101 @@ func (r *AppStruct) SetResource(name string, httpController HTTPController, rout
 	routerGroup.PUT("/:id", httpController.Update)
 	routerGroup.DELETE("/:id", httpController.Delete)
 
-	r.Resources[name] = &HTTPResource{
+	resource := HTTPResource{
 		Name:       name,
 		Controller: &httpController,
+		Path:       route.Path,
+	}
+
+	if mc, ok := httpController.(HTTPModelController); ok {
+		resource.ModelName = mc.GetModelName()
+	} else if r.GetModelSchema(name) != nil {
+		resource.ModelName = name
+	}
+
+	if isAuditableModel(r.GetModel(resource.ModelName)) {
+		routerGroup.GET("/:id/history", AuditLogHandler(resource.ModelName))
 	}
 
+	r.Resources[name] = &resource
+
 	return nil
 }
 
+// SetRoute - Register one declarative route in the router group.
+// Routes with Permission return 403 for users without the permission and 401 for requests with invalid credentials
+func (r *AppStruct) SetRoute(routerGroup *echo.Group, route *Route) error {
+	if route.Action == nil {
+		return errors.New("bolo.App.SetRoute route action is required")
+	}
+
+	if route.RateLimit != nil && (route.RateLimit.Limit <= 0 || route.RateLimit.Window <= 0) {
+		return errors.New("bolo.App.SetRoute rate limit policy limit and window are required")
+	}
+
+	handler := echo.HandlerFunc(route.Action)
+	if route.Permission != "" {
+		handler = func(c echo.Context) error {
+			if ctx, ok := c.(*RequestContext); ok && !ctx.Can(route.Permission) {
+				return newPermissionDeniedError(ctx)
+			}
+
+			return route.Action(c)
+		}
+	}
+
+	echoRoute := routerGroup.Add(route.Method, route.Path, handler)
+	route.Prefix = strings.TrimSuffix(echoRoute.Path, route.Path)
+
+	if route.RateLimit != nil && route.RateLimit.Name == "" {
+		route.RateLimit.Name = route.Method + " " + echoRoute.Path
+	}
+
+	r.routes = append(r.routes, route)
+	r.routesIndex[route.Method+" "+echoRoute.Path] = route
+
+	return nil
+}
+
+func (r *AppStruct) GetResources() map[string]*HTTPResource {
+	return r.Resources
+}
+
+func (r *AppStruct) GetRoutes() []*Route {
+	return r.routes
+}
+
+func (r *AppStruct) GetRoute(method, path string) *Route {
+	return r.routesIndex[method+" "+path]
+}
+
+// InitDatabase - Open the database connection with the name. The default connection uses the DB_* configuration
+// and named connections the DB_[NAME]_* configuration, ex: DB_ANALYTICS_URI
 func (r *AppStruct) InitDatabase(name, engine string, isDefault bool) error {
 	var err error
 	var db *gorm.DB
 
-	dbURI := r.Configuration.GetF("DB_URI", "file::memory:?charset=utf8mb4")
+	defaultURI := ""
+	if name == "default" {
+		defaultURI = "file::memory:?charset=utf8mb4"
+	}
+
+	dbURI := getDBConfiguration(r.Configuration, name, "URI", defaultURI)
+	engine = getDBConfiguration(r.Configuration, name, "ENGINE", engine)
 	dbSlowThreshold := r.Configuration.GetInt64F("DB_SLOW_THRESHOLD", 400)
 	logQuery := r.Configuration.GetF("LOG_QUERY", "")
 
 	logrus.WithFields(logrus.Fields{
-		"dbURI":           dbURI,
+		"name":            name,
+		"engine":          engine,
 		"dbSlowThreshold": dbSlowThreshold,
 		"logQuery":        logQuery,
 	}).Debug("bolo.App.InitDatabase starting db with configs")
 
 	if dbURI == "" {
-		return errors.New("bolo.App.InitDatabase DB_URI environment variable is required")
+		return errors.New("bolo.App.InitDatabase " + getDBConfigurationKey(name, "URI") + " environment variable is required")
 	}
 
-	dsn := dbURI + "?charset=utf8mb4&parseTime=True&loc=Local"
-
 	dbLogger := gorm_logger.New(log.New(os.Stdout, "\r\n", log.LstdFlags), gorm_logger.Config{
 		SlowThreshold:             time.Duration(dbSlowThreshold) * time.Millisecond,
 		LogLevel:                  gorm_logger.Warn,
@@ -452,20 +760,45 @@ func (r *AppStruct) InitDatabase(name, engine string, isDefault bool) error {
 		}
 	}
 
-	switch engine {
-	case "mysql":
-		db, err = gorm.Open(mysql.Open(dsn), gormCFG)
-	case "sqlite":
-		db, err = gorm.Open(sqlite.Open(dbURI), gormCFG)
+	db, err = openDatabaseWithRetry(r.Configuration, name, engine, dbURI, gormCFG)
+	if err != nil {
+		return errors.Wrap(err, "bolo.App.InitDatabase error on database connection")
+	}
 
-	default:
-		return errors.New("bolo.App.InitDatabase invalid database engine. Options available: mysql or sqlite")
+	err = configureDBPool(r.Configuration, name, db)
+	if err != nil {
+		return errors.Wrap(err, "bolo.App.InitDatabase error on configure connection pool")
 	}
 
+	err = registerDBMetricsCallbacks(r, name, db)
 	if err != nil {
-		return errors.Wrap(err, "bolo.App.InitDatabase error on database connection")
+		return errors.Wrap(err, "bolo.App.InitDatabase error on register metrics callbacks")
+	}
+
+	err = registerDBTracingCallbacks(r, name, db)
+	if err != nil {
+		return errors.Wrap(err, "bolo.App.InitDatabase error on register tracing callbacks")
+	}
+
+	err = models.RegisterCallbacks(db)
+	if err != nil {
+		return errors.Wrap(err, "bolo.App.InitDatabase error on register models callbacks")
+	}
+
+	err = registerAuditLogCallbacks(r, db)
+	if err != nil {
+		return errors.Wrap(err, "bolo.App.InitDatabase error on register audit log callbacks")
+	}
+
+	if replicas := splitConfigurationList(getDBConfiguration(r.Configuration, name, "REPLICAS", "")); len(replicas) > 0 {
+		err = registerDBReplicas(r.Configuration, name, engine, replicas, gormCFG, db)
+		if err != nil {
+			return errors.Wrap(err, "bolo.App.InitDatabase error on open replicas")
+		}
 	}
 
+	r.DBs[name] = db
+
 	if isDefault {
 		r.DB = db
 	}
@@ -473,14 +806,56 @@ func (r *AppStruct) InitDatabase(name, engine string, isDefault bool) error {
 	return nil
 }
 
+// SetModel - Register one model, struct models have the schema parsed for GetModelSchema
 func (r *AppStruct) SetModel(name string, f interface{}) {
 	r.Models[name] = f
+
+	s, err := NewModelSchema(name, f, getModelSchemaNamer(r))
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"name":  name,
+			"error": err.Error(),
+		}).Debug("bolo.App.SetModel model without schema")
+	}
+
+	r.modelSchemas[name] = s
 }
 
 func (r *AppStruct) GetModel(name string) interface{} {
 	return r.Models[name]
 }
 
+func (r *AppStruct) GetModelSchema(name string) *ModelSchema {
+	return r.modelSchemas[name]
+}
+
+func (r *AppStruct) GetModelSchemas() []*ModelSchema {
+	list := []*ModelSchema{}
+	for _, s := range r.modelSchemas {
+		if s != nil {
+			list = append(list, s)
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	return list
+}
+
+func (r *AppStruct) GetModelName(model interface{}) string {
+	t := reflect.Indirect(reflect.ValueOf(model)).Type()
+
+	for name, m := range r.Models {
+		if m != nil && reflect.Indirect(reflect.ValueOf(m)).Type() == t {
+			return name
+		}
+	}
+
+	return ""
+}
+
 func (r *AppStruct) SetTemplateFunction(name string, f interface{}) {
 	r.templateFunctions[name] = f
 }
@@ -591,6 +966,14 @@ func (r *AppStruct) Close() error {
 		}).Debug("bolo.App.Close error")
 	}
 
+	// export pending spans
+	err = r.tracer.Shutdown(context.Background())
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"error": fmt.Sprintf("%+v\n", err),
+		}).Debug("bolo.App.Close error on shutdown tracer")
+	}
+
 	return nil
 }
 
@@ -607,15 +990,24 @@ func NewApp(options *AppOptions) App {
 	}
 
 	app := AppStruct{
-		Options:       options,
-		Theme:         cfg.GetF("THEME", "site"),
-		Layout:        "layouts/default",
-		Configuration: cfg,
-		Events:        event.NewManager("app"),
-		router:        echo.New(),
-		routerGroups:  make(map[string]*echo.Group),
-		Resources:     make(map[string]*HTTPResource),
-		clock:         clock.New(),
+		Options:          options,
+		Theme:            cfg.GetF("THEME", "site"),
+		Layout:           "layouts/default",
+		Configuration:    cfg,
+		Events:           event.NewManager("app"),
+		router:           echo.New(),
+		routerGroups:     make(map[string]*echo.Group),
+		routerGroupPaths: make(map[string]string),
+		routerGroupCORS:  make(map[string]*CORSOptions),
+
+		routerGroupRateLimits:   make(map[string]*RateLimitPolicy),
+		routerGroupTransactions: make(map[string]bool),
+		routesIndex:             make(map[string]*Route),
+		DBs:                     make(map[string]*gorm.DB),
+		modelDBs:                make(map[string]string),
+		pluginDBs:               make(map[string]string),
+		Resources:               make(map[string]*HTTPResource),
+		clock:                   clock.New(),
 	}
 
 	app.RolesString, _ = acl.LoadRoles()
@@ -632,14 +1024,54 @@ func NewApp(options *AppOptions) App {
 	app.sanitizer = bluemonday.UGCPolicy()
 	app.sanitizer.AllowDataURIImages()
 
+	app.secret = loadAppSecret(&app)
+	app.authenticationStrategies = []AuthenticationStrategy{
+		&SessionAuthenticationStrategy{},
+		&BearerTokenAuthenticationStrategy{},
+	}
+	app.sessionStore = newSessionStoreFromConfiguration(&app)
+	app.rateLimitStore = newRateLimitStoreFromConfiguration(&app)
+
+	app.metrics = metrics.NewRegistry()
+	registerEventMetricsListener(&app)
+	registerMigrationMetricsCollector(&app)
+	registerDBPoolMetricsCollector(&app)
+	app.tracer = newTracerFromConfiguration(&app)
+
 	app.router.Binder = &CustomBinder{}
+	app.router.IPExtractor = newIPExtractorFromConfiguration(cfg)
 	app.router.HTTPErrorHandler = CustomHTTPErrorHandler(&app)
-	app.router.Validator = &helpers.CustomValidator{Validator: validator.New()}
+	app.validator = newValidator()
+	if err := documents.RegisterValidations(app.validator); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"error": err.Error(),
+		}).Error("bolo.NewApp error on register document validations")
+	}
+	database.RegisterGeoFilter()
+	translator, err := newValidationTranslator(app.validator)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"error": err.Error(),
+		}).Error("bolo.NewApp error on register validation translations")
+	}
+	app.validationTranslator = translator
+	app.router.Validator = &helpers.CustomValidator{Validator: app.validator}
+
+	app.i18n = i18n.New(cfg.GetF("DEFAULT_LANGUAGE", "en"))
+	if cfg.GetBoolF("I18N_URL_PREFIX", false) {
+		app.router.Pre(localeURLPrefixMiddleware(&app))
+	}
 
 	app.router.GET("/health", HealthCheckHandler)
+	app.router.GET("/health/live", HealthLiveHandler)
+	app.router.GET("/health/ready", healthReadyHandler(&app))
+	if cfg.GetBoolF("METRICS_ENABLED", false) {
+		app.router.GET(cfg.GetF("METRICS_PATH", "/metrics"), metricsHandler(&app))
+	}
 	app.Plugins = []Pluginer{}
 
 	app.Models = make(map[string]interface{})
+	app.modelSchemas = make(map[string]*ModelSchema)
 
 	app.templates = &template.Template{}
 
@@ -648,6 +1080,31 @@ func NewApp(options *AppOptions) App {
 
 	apiRouterGroup := app.SetRouterGroup("api", "/api")
 	apiRouterGroup.GET("", HealthCheckHandler)
+	app.SetRoute(apiRouterGroup, &Route{
+		Method:     http.MethodGet,
+		Path:       "/models",
+		Action:     modelSchemasHandler(&app),
+		Permission: PermissionViewModelSchema,
+		Model:      &ModelSchemaListResponse{},
+		Summary:    "List the registered model schemas",
+	})
+	app.SetRoute(apiRouterGroup, &Route{
+		Method:     http.MethodGet,
+		Path:       "/models/:name",
+		Action:     modelSchemaHandler(&app),
+		Permission: PermissionViewModelSchema,
+		Model:      &ModelSchemaResponse{},
+		Summary:    "Get one registered model schema",
+	})
+	if cfg.GetBoolF("OPENAPI_ENABLED", false) {
+		app.SetRoute(apiRouterGroup, &Route{
+			Method:     http.MethodGet,
+			Path:       "/openapi.json",
+			Action:     openAPIHandler(&app),
+			Permission: PermissionViewOpenAPI,
+			Summary:    "Get the OpenAPI document of the API",
+		})
+	}
 
 	app.templateFunctions = sprig.FuncMap()
 
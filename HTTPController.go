@@ -10,3 +10,9 @@ type HTTPController interface {
 	Update(c echo.Context) error
 	Delete(c echo.Context) error
 }
+
+// HTTPModelController - Optional HTTPController method with the name of the model registered with App.SetModel.
+// Resources of controllers without it use the model with the resource name
+type HTTPModelController interface {
+	GetModelName() string
+}
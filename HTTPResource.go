@@ -3,4 +3,8 @@ package bolo
 type HTTPResource struct {
 	Name       string
 	Controller *HTTPController
+	// Full path of the resource routes, ex: /api/v1/urls
+	Path string
+	// Name of the model registered with App.SetModel, used in the OpenAPI document
+	ModelName string
 }
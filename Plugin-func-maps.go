@@ -44,8 +44,11 @@ func formatDecimalWithDots(value decimal.Decimal) string {
 	return helpers.FormatDecimalWithDots(value)
 }
 
-func currentDate(format string) string {
-	return helpers.FormatCurrencyDate(format)
+// Template function with the app clock date in the SITE_TIMEZONE, see formatDate for the request timezone
+func currentDate(app App) func(layout string) (string, error) {
+	return func(layout string) (string, error) {
+		return helpers.FormatCurrencyDate(app.GetClock(), layout)
+	}
 }
 
 type ResponseMessageTPLCtx struct {
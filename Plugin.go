@@ -1,6 +1,9 @@
 package bolo
 
 import (
+	"io/fs"
+
+	"github.com/go-bolo/bolo/documents"
 	"github.com/gookit/event"
 	"github.com/sirupsen/logrus"
 )
@@ -41,14 +44,61 @@ func (p *Plugin) setTemplateFunctions(app App) error {
 	app.SetTemplateFunction("truncate", truncate)
 	app.SetTemplateFunction("formatDecimalWithDots", formatDecimalWithDots)
 	app.SetTemplateFunction("html", noEscapeHTML)
-	app.SetTemplateFunction("currentDate", currentDate)
+	app.SetTemplateFunction("currentDate", currentDate(app))
 	app.SetTemplateFunction("renderResponseMessages", renderResponseMessages)
+	app.SetTemplateFunction("csrfToken", csrfToken)
+	app.SetTemplateFunction("csrfField", csrfField)
+	app.SetTemplateFunction("cspNonce", cspNonce)
+	app.SetTemplateFunction("t", translate)
+	app.SetTemplateFunction("locale", locale)
+	app.SetTemplateFunction("formatDecimal", formatDecimal)
+	app.SetTemplateFunction("formatCurrency", formatCurrency)
+	app.SetTemplateFunction("formatDate", formatDate)
+	app.SetTemplateFunction("relativeTime", relativeTime)
+	app.SetTemplateFunction("now", currentTime)
+	app.SetTemplateFunction("maskCPF", documents.MaskCPF)
+	app.SetTemplateFunction("maskCNPJ", documents.MaskCNPJ)
+	app.SetTemplateFunction("maskCEP", documents.MaskCEP)
 
 	return nil
 }
 
+// GetLocales - Messages of the error pages
+func (p *Plugin) GetLocales() fs.FS {
+	locales, _ := fs.Sub(boloTranslations, "translations")
+	return locales
+}
+
 func (p *Plugin) GetMigrations() []*Migration {
-	return []*Migration{}
+	return []*Migration{
+		{
+			Name: "create_bolo_sessions",
+			Up: func(app App) error {
+				return app.GetDB().AutoMigrate(&SessionModel{})
+			},
+			Down: func(app App) error {
+				return app.GetDB().Migrator().DropTable(&SessionModel{})
+			},
+		},
+		{
+			Name: "create_bolo_rate_limits",
+			Up: func(app App) error {
+				return app.GetDB().AutoMigrate(&RateLimitModel{})
+			},
+			Down: func(app App) error {
+				return app.GetDB().Migrator().DropTable(&RateLimitModel{})
+			},
+		},
+		{
+			Name: "create_bolo_audit_log",
+			Up: func(app App) error {
+				return app.GetDB().AutoMigrate(&AuditLogModel{})
+			},
+			Down: func(app App) error {
+				return app.GetDB().Migrator().DropTable(&AuditLogModel{})
+			},
+		},
+	}
 }
 
 func (p *Plugin) SetTemplateFuncMap(app App) error {
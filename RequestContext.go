@@ -2,6 +2,7 @@ package bolo
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"html/template"
 	"io"
@@ -12,12 +13,15 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/go-bolo/bolo/database"
+	"github.com/go-bolo/bolo/format"
 	"github.com/go-bolo/bolo/helpers"
 	"github.com/go-bolo/bolo/pagination"
 	"github.com/go-bolo/query_parser_to_db"
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
 type RequestContextOpts struct {
@@ -45,11 +49,12 @@ func NewRequestContext(opts *RequestContextOpts) *RequestContext {
 		Domain:      domain,
 		AppOrigin:   cfg.GetF("APP_ORIGIN", protocol+"://"+domain+":"+port),
 		// Title:               "",
-		Theme:  app.GetTheme(),
-		Layout: app.GetLayout(),
-		ENV:    cfg.GetF("GO_ENV", "development"),
-		Query:  query_parser_to_db.NewQuery(50),
-		Pager:  pagination.NewPager(),
+		Theme:   app.GetTheme(),
+		Layout:  app.GetLayout(),
+		ENV:     cfg.GetF("GO_ENV", "development"),
+		Query:   query_parser_to_db.NewQuery(50),
+		Pager:   pagination.NewPager(),
+		Session: NewSession(app.GetClock().Now()),
 	}
 
 	// Is a context used on CLIs, not in HTTP request / echo then skip it
@@ -57,6 +62,7 @@ func NewRequestContext(opts *RequestContextOpts) *RequestContext {
 		return &ctx
 	}
 
+	ctx.RequestID, _ = ctx.Get(RequestIDKey).(string)
 	ctx.Pager.CurrentUrl = ctx.Request().URL.Path
 	ctx.Pager.Limit, _ = strconv.ParseInt(cfg.GetF("PAGER_LIMIT", "20"), 10, 64)
 
@@ -116,7 +122,11 @@ type RequestContext struct {
 	// authenticated user role name list
 	Roles []string
 
-	Session SessionData
+	Session *Session
+	// Content-Security-Policy nonce for inline scripts, see the cspNonce template function
+	CSPNonce string
+	// Request X-Request-ID, used in logs
+	RequestID string
 
 	Widgets   map[string]map[string]string
 	Theme     string
@@ -129,6 +139,15 @@ type RequestContext struct {
 	responseMessages []*ResponseMessage
 
 	ENV string
+
+	// database context with the route query timeout and the request transaction, see DB()
+	dbCtx context.Context
+	dbTx  *gorm.DB
+
+	// resolved request locale, see GetLocale()
+	locale string
+	// formatter of the request locale and timezone, see GetFormatter()
+	formatter *format.Formatter
 }
 
 type ResponseMessage struct {
@@ -372,13 +391,22 @@ func (c *RequestContext) NoContent(code int) error {
 }
 
 // Redirect redirects the request to a provided URL with status code.
+// Response messages are persisted as flash messages to be rendered in the next request.
 func (c *RequestContext) Redirect(code int, url string) error {
+	err := c.persistFlashMessages()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"error": fmt.Sprintf("%+v\n", err),
+			"url":   url,
+		}).Warn("bolo.RequestContext.Redirect error on persist flash messages")
+	}
+
 	return c.echoContext.Redirect(code, url)
 }
 
-// Error invokes the registered HTTP error handler. Generally used by middleware.
+// Error invokes the registered HTTP error handler with the request context. Generally used by middleware.
 func (c *RequestContext) Error(err error) {
-	c.echoContext.Error(err)
+	c.App.GetRouter().HTTPErrorHandler(err, c)
 }
 
 // Handler returns the matched handler by router.
@@ -415,10 +443,6 @@ func (c *RequestContext) Reset(r *http.Request, w http.ResponseWriter) {
 	c.echoContext.Reset(r, w)
 }
 
-type SessionData struct {
-	UserID string
-}
-
 func (r *RequestContext) Set(name string, value interface{}) {
 	r.echoContext.Set(name, value)
 }
@@ -553,6 +577,23 @@ func (r *RequestContext) ParseQueryFromReq(c echo.Context) error {
 	return nil
 }
 
+// GetGeoQuery - Get the distance query from the near=lat,lng, radius (meters) and sort=distance query params.
+// Returns nil without the near param, use it in list endpoints with db.Scopes(q.Scope("location")).
+// Models with one geo filter tag get the same filters in Query.SetDatabaseQueryForModel, see database.ParseGeoQuery
+func (r *RequestContext) GetGeoQuery() (*database.GeoQuery, error) {
+	near := r.QueryParam("near")
+	if near == "" {
+		return nil, nil
+	}
+
+	q, err := database.ParseGeoQuery(near, r.QueryParam("radius"), r.QueryParam("sort") == "distance")
+	if err != nil {
+		return nil, &HTTPError{Code: http.StatusBadRequest, Message: err.Error(), Internal: err}
+	}
+
+	return q, nil
+}
+
 func (r *RequestContext) GetAuthenticatedRoles() *[]string {
 	if r.IsAuthenticated {
 		return &r.Roles
@@ -0,0 +1,52 @@
+package bolo_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-bolo/bolo"
+	"github.com/go-bolo/bolo/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestContext_GetGeoQuery(t *testing.T) {
+	app := GetTestApp()
+	err := app.Bootstrap()
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name    string
+		query   string
+		want    *database.GeoQuery
+		wantErr bool
+	}{
+		{name: "without near", query: "radius=10", want: nil},
+		{
+			name:  "near with radius and sort",
+			query: "near=-27.5969,-48.5495&radius=2500&sort=distance",
+			want:  &database.GeoQuery{Center: database.NewGeoPoint(-27.5969, -48.5495), Radius: 2500, OrderByDistance: true},
+		},
+		{name: "invalid near", query: "near=-27.5969", wantErr: true},
+		{name: "invalid latitude", query: "near=-127.5,10", wantErr: true},
+		{name: "invalid radius", query: "near=1,2&radius=-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/places?"+tt.query, nil)
+			c := app.GetRouter().NewContext(req, httptest.NewRecorder())
+			ctx := bolo.NewRequestContext(&bolo.RequestContextOpts{App: app, EchoContext: c})
+
+			q, err := ctx.GetGeoQuery()
+			if tt.wantErr {
+				if assert.IsType(t, &bolo.HTTPError{}, err) {
+					assert.Equal(t, http.StatusBadRequest, err.(*bolo.HTTPError).Code)
+				}
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, q)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package bolo
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-bolo/bolo/tracing"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// Echo context key where the request ID is stored
+const RequestIDKey = "requestID"
+
+// Check if one received X-Request-ID can be reused in logs and responses
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+type requestIDContextKey struct{}
+
+// RequestIDFromContext - Get the request ID from one request context, ex: in gorm callbacks of queries made with RequestContext.DB()
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	id, _ := ctx.Value(requestIDContextKey{}).(string)
+	return id
+}
+
+// Middleware that accepts or generates the request X-Request-ID and echoes it in the response
+func requestIDMiddleware(app App) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			id := c.Request().Header.Get(echo.HeaderXRequestID)
+			if !isValidRequestID(id) {
+				id = RandomToken(16)
+			}
+
+			c.Set(RequestIDKey, id)
+			c.SetRequest(c.Request().WithContext(context.WithValue(c.Request().Context(), requestIDContextKey{}, id)))
+			c.Response().Header().Set(echo.HeaderXRequestID, id)
+
+			return next(c)
+		}
+	}
+}
+
+// Log - Get one log entry with the request ID, route, method, trace ID and authenticated user ID
+func (r *RequestContext) Log() *logrus.Entry {
+	fields := logrus.Fields{
+		"requestID": r.RequestID,
+	}
+
+	if r.echoContext != nil && r.Request() != nil {
+		fields["method"] = r.Request().Method
+		fields["route"] = r.Path()
+
+		if sc := tracing.SpanContextFromContext(r.Request().Context()); sc.IsValid() {
+			fields["traceID"] = sc.TraceID.String()
+			fields["spanID"] = sc.SpanID.String()
+		}
+	}
+
+	if r.IsAuthenticated && r.AuthenticatedUser != nil {
+		fields["userID"] = r.AuthenticatedUser.GetID()
+	}
+
+	return logrus.WithFields(fields)
+}
+
+// Middleware that writes one structured access log line per request
+func accessLogMiddleware(app App) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.(*RequestContext)
+			start := time.Now()
+
+			// run the error handler now to log the final response status
+			err := handleErrorOnce(ctx, next(ctx))
+
+			req := ctx.Request()
+			res := ctx.Response()
+
+			bytesIn, _ := strconv.ParseInt(req.Header.Get(echo.HeaderContentLength), 10, 64)
+
+			entry := ctx.Log().WithFields(logrus.Fields{
+				"uri":       req.RequestURI,
+				"status":    res.Status,
+				"latencyMs": float64(time.Since(start).Microseconds()) / 1000,
+				"bytesIn":   bytesIn,
+				"bytesOut":  res.Size,
+				"remoteIP":  ctx.RealIP(),
+				"userAgent": req.UserAgent(),
+			})
+
+			if res.Status >= http.StatusInternalServerError {
+				entry.Error("bolo.accessLog")
+			} else {
+				entry.Info("bolo.accessLog")
+			}
+
+			return err
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package bolo_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-bolo/bolo"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDAndAccessLog(t *testing.T) {
+	app := GetTestApp()
+	err := app.Bootstrap()
+	assert.Nil(t, err)
+
+	var requestID string
+	app.GetRouter().GET("/traced", func(c echo.Context) error {
+		ctx := c.(*bolo.RequestContext)
+		requestID = ctx.RequestID
+		assert.Equal(t, requestID, ctx.Log().Data["requestID"])
+		assert.Equal(t, "/traced", ctx.Log().Data["route"])
+		return c.String(http.StatusOK, "ok")
+	})
+
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	tests := []struct {
+		name     string
+		received string
+		reused   bool
+	}{
+		{name: "should reuse valid request IDs", received: "abc-123", reused: true},
+		{name: "should generate a request ID if not received"},
+		{name: "should replace invalid request IDs", received: "bad id\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook.Reset()
+
+			req := httptest.NewRequest(http.MethodGet, "/traced", nil)
+			if tt.received != "" {
+				req.Header.Set(echo.HeaderXRequestID, tt.received)
+			}
+			rec := httptest.NewRecorder()
+			app.GetRouter().ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.NotEmpty(t, requestID)
+			assert.Equal(t, requestID, rec.Header().Get(echo.HeaderXRequestID))
+			if tt.reused {
+				assert.Equal(t, tt.received, requestID)
+			} else {
+				assert.NotEqual(t, tt.received, requestID)
+			}
+
+			var accessLog *logrus.Entry
+			for _, e := range hook.AllEntries() {
+				if e.Message == "bolo.accessLog" {
+					accessLog = e
+				}
+			}
+			if assert.NotNil(t, accessLog) {
+				assert.Equal(t, requestID, accessLog.Data["requestID"])
+				assert.Equal(t, http.StatusOK, accessLog.Data["status"])
+				assert.Equal(t, int64(2), accessLog.Data["bytesOut"])
+			}
+		})
+	}
+}
@@ -0,0 +1,408 @@
+package bolo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/go-bolo/bolo/database"
+	"github.com/go-bolo/bolo/models"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	AuditLogActionCreate = "create"
+	AuditLogActionUpdate = "update"
+	AuditLogActionDelete = "delete"
+
+	// Permission to read the change history of records, see AuditLogHandler
+	PermissionViewAuditLog = "view_audit_log"
+)
+
+// Fields that change in all updates and are not stored in update diffs
+var auditLogIgnoredUpdateFields = map[string]bool{
+	"UpdatedAt": true,
+	"UpdatedBy": true,
+	"Version":   true,
+}
+
+// AuditLogChange - Old and new JSON values of one field
+type AuditLogChange struct {
+	Old json.RawMessage `json:"old,omitempty"`
+	New json.RawMessage `json:"new,omitempty"`
+}
+
+// AuditLogModel - One change of one record of one models.Auditable model
+type AuditLogModel struct {
+	ID        uint64                                    `gorm:"column:id;primaryKey" json:"id"`
+	ModelName string                                    `gorm:"column:model_name;type:varchar(100);not null;index:idx_bolo_audit_log_record" json:"modelName"`
+	RecordID  string                                    `gorm:"column:record_id;type:varchar(100);not null;index:idx_bolo_audit_log_record" json:"recordId"`
+	Action    string                                    `gorm:"column:action;type:varchar(20);not null" json:"action"`
+	Changes   database.JSON[map[string]*AuditLogChange] `gorm:"column:changes" json:"changes"`
+	UserID    string                                    `gorm:"column:user_id;type:varchar(255)" json:"userId,omitempty"`
+	RequestID string                                    `gorm:"column:request_id;type:varchar(128)" json:"requestId,omitempty"`
+	CreatedAt time.Time                                 `gorm:"column:created_at;not null" json:"createdAt"`
+}
+
+func (m *AuditLogModel) TableName() string {
+	return "bolo_audit_log"
+}
+
+type AuditLogListResponse struct {
+	BaseListReponse
+	Records []*AuditLogModel `json:"records"`
+}
+
+// FindAuditLog - Get the change history of one record, oldest first
+func FindAuditLog(db *gorm.DB, modelName, recordID string) ([]*AuditLogModel, error) {
+	list := []*AuditLogModel{}
+	err := db.
+		Where("model_name = ? AND record_id = ?", modelName, recordID).
+		Order("created_at ASC, id ASC").
+		Find(&list).Error
+
+	return list, err
+}
+
+// AuditLogHandler - Handler that returns the change history of one record of the model, with the :id route param.
+// SetResource mounts it in /:id/history for models.Auditable models, ex: router.GET("/:id/history", bolo.AuditLogHandler("url"))
+func AuditLogHandler(modelName string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.(*RequestContext)
+
+		if !ctx.Can(PermissionViewAuditLog) {
+			return newPermissionDeniedError(ctx)
+		}
+
+		list, err := FindAuditLog(ctx.DB(), modelName, c.Param("id"))
+		if err != nil {
+			return fmt.Errorf("bolo.AuditLogHandler error on find audit log: %w", err)
+		}
+
+		resp := AuditLogListResponse{Records: list}
+		resp.Meta.Count = int64(len(list))
+
+		return c.JSON(http.StatusOK, &resp)
+	}
+}
+
+// Register gorm callbacks that store the changes of models.Auditable models in the bolo_audit_log table
+func registerAuditLogCallbacks(app App, db *gorm.DB) error {
+	cb := db.Callback()
+
+	if err := cb.Create().After("gorm:create").Register("bolo:audit_log_after_create", auditLogAfterCreate(app)); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("bolo:audit_log_before_update", auditLogLoadOld); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Register("bolo:audit_log_after_update", auditLogAfterUpdate(app)); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("bolo:audit_log_before_delete", auditLogLoadOld); err != nil {
+		return err
+	}
+
+	return cb.Delete().After("gorm:delete").Register("bolo:audit_log_after_delete", auditLogAfterDelete(app))
+}
+
+func isAuditable(tx *gorm.DB) bool {
+	if tx.Error != nil || tx.Statement.Schema == nil || tx.Statement.DryRun {
+		return false
+	}
+
+	return isAuditableModel(reflect.New(tx.Statement.Schema.ModelType).Interface())
+}
+
+func isAuditableModel(model interface{}) bool {
+	if model == nil {
+		return false
+	}
+
+	a, ok := reflect.New(reflect.Indirect(reflect.ValueOf(model)).Type()).Interface().(models.Auditable)
+	return ok && a.IsAuditable()
+}
+
+// Get the primary key of one record as string, empty if not set
+func getAuditLogRecordID(ctx context.Context, s *schema.Schema, rv reflect.Value) string {
+	if s.PrioritizedPrimaryField == nil {
+		return ""
+	}
+
+	v, isZero := s.PrioritizedPrimaryField.ValueOf(ctx, rv)
+	if isZero {
+		return ""
+	}
+
+	return fmt.Sprint(v)
+}
+
+// Get the JSON value of each audited field
+func getAuditLogValues(ctx context.Context, s *schema.Schema, rv reflect.Value) map[string]json.RawMessage {
+	values := map[string]json.RawMessage{}
+
+	for _, field := range s.Fields {
+		if field.DBName == "" || field.Tag.Get("audit") == "-" {
+			continue
+		}
+
+		v, _ := field.ValueOf(ctx, rv)
+		data, err := json.Marshal(v)
+		if err != nil {
+			continue
+		}
+
+		values[field.Name] = data
+	}
+
+	return values
+}
+
+// Max number of records loaded to audit one bulk update or delete, bigger changes are logged as not audited
+const auditLogMaxBulkRecords = 1000
+
+// One record loaded before one update or delete
+type auditLogOldRecord struct {
+	ID     string
+	PK     interface{}
+	Values map[string]json.RawMessage
+}
+
+// Get the primary key values of the records in the statement model or slice
+func getAuditLogStatementPKs(tx *gorm.DB) []interface{} {
+	pks := []interface{}{}
+	field := tx.Statement.Schema.PrioritizedPrimaryField
+
+	addRecord := func(rv reflect.Value) {
+		if rv.Kind() != reflect.Struct {
+			return
+		}
+
+		if v, isZero := field.ValueOf(tx.Statement.Context, rv); !isZero {
+			pks = append(pks, v)
+		}
+	}
+
+	rv := tx.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			addRecord(reflect.Indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		addRecord(rv)
+	}
+
+	return pks
+}
+
+// Load the records changed by one update or delete before the change, with the statement primary keys and
+// conditions. Supports single record, slice and bulk (Model().Where().Updates(), Where().Delete()) changes
+func auditLogLoadOld(tx *gorm.DB) {
+	if !isAuditable(tx) {
+		return
+	}
+
+	s := tx.Statement.Schema
+	if s.PrioritizedPrimaryField == nil {
+		logrus.WithFields(logrus.Fields{
+			"table": s.Table,
+		}).Warn("bolo.auditLogLoadOld change of model without primary key not audited")
+		return
+	}
+
+	pks := getAuditLogStatementPKs(tx)
+	where, hasWhere := tx.Statement.Clauses["WHERE"].Expression.(clause.Where)
+	if len(pks) == 0 && (!hasWhere || len(where.Exprs) == 0) {
+		// gorm blocks global updates and deletes
+		return
+	}
+
+	query := UsePrimaryDB(tx.Session(&gorm.Session{NewDB: true, SkipHooks: true}))
+	if tx.Statement.Unscoped {
+		query = query.Unscoped()
+	}
+	if len(pks) > 0 {
+		query = query.Where(map[string]interface{}{s.PrioritizedPrimaryField.DBName: pks})
+	}
+	if hasWhere && len(where.Exprs) > 0 {
+		query = query.Clauses(clause.Where{Exprs: where.Exprs})
+	}
+
+	list := reflect.New(reflect.SliceOf(reflect.PtrTo(s.ModelType)))
+	err := query.Model(reflect.New(s.ModelType).Interface()).
+		Limit(auditLogMaxBulkRecords + 1).
+		Find(list.Interface()).Error
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"error": err.Error(),
+			"table": s.Table,
+		}).Error("bolo.auditLogLoadOld error on load the changed records")
+		return
+	}
+
+	rv := list.Elem()
+	if rv.Len() > auditLogMaxBulkRecords {
+		logrus.WithFields(logrus.Fields{
+			"table": s.Table,
+			"max":   auditLogMaxBulkRecords,
+		}).Warn("bolo.auditLogLoadOld bulk change with too many records not audited")
+		return
+	}
+
+	records := []*auditLogOldRecord{}
+	for i := 0; i < rv.Len(); i++ {
+		record := rv.Index(i).Elem()
+		pk, _ := s.PrioritizedPrimaryField.ValueOf(tx.Statement.Context, record)
+
+		records = append(records, &auditLogOldRecord{
+			ID:     getAuditLogRecordID(tx.Statement.Context, s, record),
+			PK:     pk,
+			Values: getAuditLogValues(tx.Statement.Context, s, record),
+		})
+	}
+
+	tx.InstanceSet("bolo:auditLogOld", records)
+}
+
+func auditLogAfterCreate(app App) func(tx *gorm.DB) {
+	return func(tx *gorm.DB) {
+		if !isAuditable(tx) {
+			return
+		}
+
+		saveRecord := func(rv reflect.Value) {
+			changes := map[string]*AuditLogChange{}
+			for name, value := range getAuditLogValues(tx.Statement.Context, tx.Statement.Schema, rv) {
+				changes[name] = &AuditLogChange{New: value}
+			}
+
+			id := getAuditLogRecordID(tx.Statement.Context, tx.Statement.Schema, rv)
+			saveAuditLog(app, tx, AuditLogActionCreate, id, changes)
+		}
+
+		rv := tx.Statement.ReflectValue
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < rv.Len(); i++ {
+				saveRecord(reflect.Indirect(rv.Index(i)))
+			}
+		case reflect.Struct:
+			saveRecord(rv)
+		}
+	}
+}
+
+func auditLogAfterUpdate(app App) func(tx *gorm.DB) {
+	return func(tx *gorm.DB) {
+		v, ok := tx.InstanceGet("bolo:auditLogOld")
+		if !ok || tx.Error != nil || tx.RowsAffected == 0 {
+			return
+		}
+		records := v.([]*auditLogOldRecord)
+		if len(records) == 0 {
+			return
+		}
+
+		s := tx.Statement.Schema
+		pks := []interface{}{}
+		for _, record := range records {
+			pks = append(pks, record.PK)
+		}
+
+		// reload the records, updates with maps or columns don't change all fields of the model
+		list := reflect.New(reflect.SliceOf(reflect.PtrTo(s.ModelType)))
+		err := UsePrimaryDB(tx.Session(&gorm.Session{NewDB: true, SkipHooks: true})).
+			Unscoped().
+			Where(map[string]interface{}{s.PrioritizedPrimaryField.DBName: pks}).
+			Find(list.Interface()).Error
+		if err != nil {
+			return
+		}
+
+		updated := map[string]map[string]json.RawMessage{}
+		rv := list.Elem()
+		for i := 0; i < rv.Len(); i++ {
+			record := rv.Index(i).Elem()
+			updated[getAuditLogRecordID(tx.Statement.Context, s, record)] = getAuditLogValues(tx.Statement.Context, s, record)
+		}
+
+		for _, record := range records {
+			values, ok := updated[record.ID]
+			if !ok {
+				continue
+			}
+
+			changes := map[string]*AuditLogChange{}
+			for name, value := range values {
+				if auditLogIgnoredUpdateFields[name] || bytes.Equal(record.Values[name], value) {
+					continue
+				}
+
+				changes[name] = &AuditLogChange{Old: record.Values[name], New: value}
+			}
+
+			if len(changes) == 0 {
+				continue
+			}
+
+			saveAuditLog(app, tx, AuditLogActionUpdate, record.ID, changes)
+		}
+	}
+}
+
+func auditLogAfterDelete(app App) func(tx *gorm.DB) {
+	return func(tx *gorm.DB) {
+		v, ok := tx.InstanceGet("bolo:auditLogOld")
+		if !ok || tx.Error != nil || tx.RowsAffected == 0 {
+			return
+		}
+
+		for _, record := range v.([]*auditLogOldRecord) {
+			changes := map[string]*AuditLogChange{}
+			for name, value := range record.Values {
+				changes[name] = &AuditLogChange{Old: value}
+			}
+
+			saveAuditLog(app, tx, AuditLogActionDelete, record.ID, changes)
+		}
+	}
+}
+
+// Store one audit log entry in the same connection or transaction of the change
+func saveAuditLog(app App, tx *gorm.DB, action, recordID string, changes map[string]*AuditLogChange) {
+	ctx := tx.Statement.Context
+
+	modelName := app.GetModelName(reflect.New(tx.Statement.Schema.ModelType).Interface())
+	if modelName == "" {
+		modelName = tx.Statement.Schema.Table
+	}
+
+	entry := AuditLogModel{
+		ModelName: modelName,
+		RecordID:  recordID,
+		Action:    action,
+		Changes:   database.NewJSON(changes),
+		UserID:    models.AuthorFromContext(ctx),
+		RequestID: RequestIDFromContext(ctx),
+		CreatedAt: app.GetClock().Now(),
+	}
+
+	err := tx.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Create(&entry).Error
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"error":     err.Error(),
+			"modelName": modelName,
+			"recordID":  recordID,
+			"action":    action,
+		}).Error("bolo.saveAuditLog error on save audit log")
+	}
+}
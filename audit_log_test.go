@@ -0,0 +1,234 @@
+package bolo_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-bolo/bolo"
+	"github.com/go-bolo/bolo/models"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type auditTestProduct struct {
+	ID uint64 `gorm:"primaryKey" json:"id"`
+	models.Audited
+	models.Authorship
+	models.SoftDelete
+
+	Name     string `json:"name"`
+	Price    int    `json:"price"`
+	Password string `json:"-" audit:"-"`
+}
+
+func TestAuditLog(t *testing.T) {
+	os.Setenv("DB_URI", filepath.Join(t.TempDir(), "bolo.db"))
+	defer os.Unsetenv("DB_URI")
+
+	app := GetTestApp()
+	err := app.Bootstrap()
+	assert.Nil(t, err)
+	assert.Nil(t, app.GetDB().AutoMigrate(&bolo.AuditLogModel{}, &auditTestProduct{}, &transactionTestRecord{}))
+	app.SetModel("product", &auditTestProduct{})
+
+	app.SetRoute(app.GetRouterGroup("main"), &bolo.Route{
+		Method: http.MethodPost,
+		Path:   "products",
+		Action: func(c echo.Context) error {
+			ctx := c.(*bolo.RequestContext)
+			ctx.SetAuthenticatedUserAndFillRoles(&UserModel{ID: "7", Roles: []string{"authenticated"}})
+
+			product := auditTestProduct{}
+			if err := c.Bind(&product); err != nil {
+				return err
+			}
+			if err := ctx.DB().Save(&product).Error; err != nil {
+				return err
+			}
+
+			return c.JSON(http.StatusOK, &product)
+		},
+	})
+	app.SetRoute(app.GetRouterGroup("main"), &bolo.Route{
+		Method: http.MethodGet,
+		Path:   "products/:id/history",
+		Action: func(c echo.Context) error {
+			ctx := c.(*bolo.RequestContext)
+			ctx.SetAuthenticatedUserAndFillRoles(&UserModel{ID: "7", Roles: c.QueryParams()["role"]})
+
+			return bolo.AuditLogHandler("product")(c)
+		},
+	})
+
+	serve := func(method, url, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		req.Header.Set(echo.HeaderAccept, "application/json")
+		req.Header.Set(echo.HeaderContentType, "application/json")
+		req.Header.Set(echo.HeaderXRequestID, "audit-request")
+		rec := httptest.NewRecorder()
+		app.GetRouter().ServeHTTP(rec, req)
+		return rec
+	}
+
+	product := auditTestProduct{}
+	rec := serve(http.MethodPost, "/products", `{"name":"chair","price":10}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &product))
+	id := fmt.Sprint(product.ID)
+
+	t.Run("should log the create with the user and request", func(t *testing.T) {
+		list, err := bolo.FindAuditLog(app.GetDB(), "product", id)
+		assert.Nil(t, err)
+		assert.Len(t, list, 1)
+
+		entry := list[0]
+		assert.Equal(t, bolo.AuditLogActionCreate, entry.Action)
+		assert.Equal(t, "7", entry.UserID)
+		assert.Equal(t, "audit-request", entry.RequestID)
+		assert.Equal(t, `"chair"`, string(entry.Changes.Data["Name"].New))
+		assert.Nil(t, entry.Changes.Data["Name"].Old)
+		assert.NotContains(t, entry.Changes.Data, "Password")
+	})
+
+	t.Run("should log only the changed fields of updates", func(t *testing.T) {
+		body := fmt.Sprintf(`{"id":%d,"name":"chair","price":15}`, product.ID)
+		assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/products", body).Code)
+
+		// without changes
+		assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/products", body).Code)
+		// only excluded fields
+		assert.Nil(t, app.GetDB().Model(&auditTestProduct{ID: product.ID}).Update("password", "secret").Error)
+
+		list, err := bolo.FindAuditLog(app.GetDB(), "product", id)
+		assert.Nil(t, err)
+		assert.Len(t, list, 2)
+
+		entry := list[1]
+		assert.Equal(t, bolo.AuditLogActionUpdate, entry.Action)
+		assert.Len(t, entry.Changes.Data, 1)
+		assert.Equal(t, "10", string(entry.Changes.Data["Price"].Old))
+		assert.Equal(t, "15", string(entry.Changes.Data["Price"].New))
+	})
+
+	t.Run("should log deletes with the old values", func(t *testing.T) {
+		assert.Nil(t, app.GetDB().Delete(&auditTestProduct{ID: product.ID}).Error)
+
+		list, err := bolo.FindAuditLog(app.GetDB(), "product", id)
+		assert.Nil(t, err)
+		assert.Len(t, list, 3)
+
+		entry := list[2]
+		assert.Equal(t, bolo.AuditLogActionDelete, entry.Action)
+		assert.Equal(t, "", entry.UserID)
+		assert.Equal(t, `"chair"`, string(entry.Changes.Data["Name"].Old))
+	})
+
+	t.Run("should not log models without Audited", func(t *testing.T) {
+		assert.Nil(t, app.GetDB().Create(&transactionTestRecord{Name: "not audited"}).Error)
+
+		var count int64
+		assert.Nil(t, app.GetDB().Model(&bolo.AuditLogModel{}).Count(&count).Error)
+		assert.Equal(t, int64(3), count)
+	})
+
+	t.Run("should return the history only with the view_audit_log permission", func(t *testing.T) {
+		rec := serve(http.MethodGet, "/products/"+id+"/history?role=authenticated", "")
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+
+		rec = serve(http.MethodGet, "/products/"+id+"/history?role=administrator", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		resp := bolo.AuditLogListResponse{}
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, int64(3), resp.Meta.Count)
+		assert.Len(t, resp.Records, 3)
+		assert.Equal(t, []string{"create", "update", "delete"}, []string{resp.Records[0].Action, resp.Records[1].Action, resp.Records[2].Action})
+	})
+}
+
+func TestAuditLog_BulkChanges(t *testing.T) {
+	os.Setenv("DB_URI", filepath.Join(t.TempDir(), "bolo.db"))
+	defer os.Unsetenv("DB_URI")
+
+	app := GetTestApp()
+	assert.Nil(t, app.Bootstrap())
+	assert.Nil(t, app.GetDB().AutoMigrate(&bolo.AuditLogModel{}, &auditTestProduct{}))
+	app.SetModel("product", &auditTestProduct{})
+
+	products := []*auditTestProduct{{Name: "chair", Price: 10}, {Name: "table", Price: 10}, {Name: "lamp", Price: 20}}
+	assert.Nil(t, app.GetDB().Create(&products).Error)
+
+	history := func(p *auditTestProduct) []*bolo.AuditLogModel {
+		list, err := bolo.FindAuditLog(app.GetDB(), "product", fmt.Sprint(p.ID))
+		assert.Nil(t, err)
+		return list
+	}
+
+	t.Run("should log the records of bulk updates", func(t *testing.T) {
+		err := app.GetDB().Model(&auditTestProduct{}).Where("price = ?", 10).Updates(map[string]interface{}{"price": 12}).Error
+		assert.Nil(t, err)
+
+		for _, p := range products[:2] {
+			list := history(p)
+			if assert.Len(t, list, 2) {
+				assert.Equal(t, bolo.AuditLogActionUpdate, list[1].Action)
+				assert.Equal(t, "10", string(list[1].Changes.Data["Price"].Old))
+				assert.Equal(t, "12", string(list[1].Changes.Data["Price"].New))
+			}
+		}
+		assert.Len(t, history(products[2]), 1)
+	})
+
+	t.Run("should log the records of bulk deletes", func(t *testing.T) {
+		assert.Nil(t, app.GetDB().Where("name = ?", "chair").Delete(&auditTestProduct{}).Error)
+
+		list := history(products[0])
+		if assert.Len(t, list, 3) {
+			assert.Equal(t, bolo.AuditLogActionDelete, list[2].Action)
+			assert.Equal(t, `"chair"`, string(list[2].Changes.Data["Name"].Old))
+		}
+		assert.Len(t, history(products[1]), 2)
+	})
+
+	t.Run("should log the records of slice deletes", func(t *testing.T) {
+		assert.Nil(t, app.GetDB().Delete(products).Error)
+
+		// the chair is already deleted
+		assert.Len(t, history(products[0]), 3)
+		for _, p := range products[1:] {
+			list := history(p)
+			assert.Equal(t, bolo.AuditLogActionDelete, list[len(list)-1].Action)
+		}
+	})
+}
+
+type auditTestProductController struct {
+	URLController
+}
+
+func (ctl *auditTestProductController) GetModelName() string {
+	return "product"
+}
+
+func TestApp_SetResource_AuditLogHistory(t *testing.T) {
+	app := GetTestApp()
+	assert.Nil(t, app.Bootstrap())
+
+	app.SetModel("product", &auditTestProduct{})
+	app.SetResource("product-api", &auditTestProductController{}, app.SetRouterGroup("product-api", "/api/v1/products"))
+	app.SetResource("post-api", &openAPITestController{}, app.SetRouterGroup("post-api", "/api/v1/posts"))
+
+	paths := map[string]bool{}
+	for _, r := range app.GetRouter().Routes() {
+		paths[r.Method+" "+r.Path] = true
+	}
+
+	assert.True(t, paths["GET /api/v1/products/:id/history"])
+	assert.False(t, paths["GET /api/v1/posts/:id/history"])
+}
@@ -0,0 +1,282 @@
+package bolo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ErrUserNotFound - Returned by UserInterface.FillById or user loaders if the user doesn't exist
+var ErrUserNotFound = errors.New("user not found")
+
+// UserLoader - Load one user by id, registered by the app with App.SetUserLoader.
+// Should return a nil user and nil error if the user doesn't exist, other errors are handled as server errors
+type UserLoader func(ctx *RequestContext, userID string) (UserInterface, error)
+
+// NewUserLoaderFromModel - Build a UserLoader that uses UserInterface.FillById from a new model instance.
+// FillById ErrUserNotFound and gorm.ErrRecordNotFound errors load a nil user
+func NewUserLoaderFromModel(newUser func() UserInterface) UserLoader {
+	return func(ctx *RequestContext, userID string) (UserInterface, error) {
+		user := newUser()
+		err := user.FillById(userID)
+		if errors.Is(err, ErrUserNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return user, nil
+	}
+}
+
+// AuthenticationStrategy - Resolve the request user from one credential type.
+// Authenticate should return a nil user and nil error if the request has no credentials for the strategy,
+// and ErrInvalidToken or ErrExpiredToken for invalid credentials. Other errors are handled as server errors
+type AuthenticationStrategy interface {
+	GetName() string
+	Authenticate(ctx *RequestContext) (UserInterface, error)
+}
+
+// SessionAuthenticationStrategy - Authenticate with the user id stored in the request session by RequestContext.Login
+type SessionAuthenticationStrategy struct{}
+
+func (s *SessionAuthenticationStrategy) GetName() string {
+	return "session"
+}
+
+func (s *SessionAuthenticationStrategy) Authenticate(ctx *RequestContext) (UserInterface, error) {
+	userID := ctx.Session.GetString(SessionUserIDKey)
+	if userID == "" {
+		return nil, nil
+	}
+
+	user, err := loadAuthenticatedUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil && ctx.App.GetUserLoader() != nil {
+		// the user was deleted, continue as anonymous in this and next requests
+		logrus.WithFields(logrus.Fields{
+			"userID": userID,
+		}).Debug("bolo.SessionAuthenticationStrategy.Authenticate session user not found")
+
+		ctx.Session.Delete(SessionUserIDKey)
+	}
+
+	return user, nil
+}
+
+// BearerTokenAuthenticationStrategy - Authenticate with `Authorization: Bearer [token]` tokens created with NewAuthenticationToken
+type BearerTokenAuthenticationStrategy struct{}
+
+func (s *BearerTokenAuthenticationStrategy) GetName() string {
+	return "bearer"
+}
+
+func (s *BearerTokenAuthenticationStrategy) Authenticate(ctx *RequestContext) (UserInterface, error) {
+	token := getBearerToken(ctx.Request())
+	if token == "" {
+		return nil, nil
+	}
+
+	claims, err := ParseAuthenticationToken(ctx.App, token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := loadAuthenticatedUser(ctx, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil && ctx.App.GetUserLoader() != nil {
+		return nil, ErrInvalidToken
+	}
+
+	return user, nil
+}
+
+// APIKeyAuthenticationStrategy - Authenticate with one API key sent in the X-API-Key header
+type APIKeyAuthenticationStrategy struct {
+	// Header name, default: X-API-Key
+	Header string
+	// Return the user for the key or a nil user if the key is invalid
+	Loader func(ctx *RequestContext, key string) (UserInterface, error)
+}
+
+func (s *APIKeyAuthenticationStrategy) GetName() string {
+	return "apiKey"
+}
+
+func (s *APIKeyAuthenticationStrategy) Authenticate(ctx *RequestContext) (UserInterface, error) {
+	header := s.Header
+	if header == "" {
+		header = "X-API-Key"
+	}
+
+	key := ctx.Request().Header.Get(header)
+	if key == "" {
+		return nil, nil
+	}
+
+	if s.Loader == nil {
+		logrus.WithFields(logrus.Fields{
+			"header": header,
+		}).Warn("bolo.APIKeyAuthenticationStrategy.Authenticate strategy without Loader")
+		return nil, nil
+	}
+
+	user, err := s.Loader(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, ErrInvalidToken
+	}
+
+	return user, nil
+}
+
+func loadAuthenticatedUser(ctx *RequestContext, userID string) (UserInterface, error) {
+	loader := ctx.App.GetUserLoader()
+	if loader == nil {
+		logrus.WithFields(logrus.Fields{
+			"userID": userID,
+		}).Warn("bolo.loadAuthenticatedUser user loader not registered, use App.SetUserLoader")
+		return nil, nil
+	}
+
+	user, err := loader(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "bolo.loadAuthenticatedUser error on load user")
+	}
+
+	return user, nil
+}
+
+func getBearerToken(r *http.Request) string {
+	authorization := r.Header.Get(echo.HeaderAuthorization)
+	if len(authorization) > 7 && strings.EqualFold(authorization[:7], "bearer ") {
+		return strings.TrimSpace(authorization[7:])
+	}
+
+	return ""
+}
+
+// Middleware that run the app authentication strategies in order and fill the authenticated user in the request context
+func authenticationMiddleware(app App) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.(*RequestContext)
+
+			for _, strategy := range app.GetAuthenticationStrategies() {
+				user, err := strategy.Authenticate(ctx)
+				if isInvalidCredentialsError(err) {
+					// routes that need authentication return 401, see newPermissionDeniedError
+					logrus.WithFields(logrus.Fields{
+						"strategy": strategy.GetName(),
+						"path":     c.Path(),
+						"error":    err.Error(),
+					}).Debug("bolo.authenticationMiddleware invalid credentials")
+
+					ctx.Set("authenticationError", err)
+					continue
+				}
+				if err != nil {
+					return &HTTPError{
+						Code:     http.StatusInternalServerError,
+						Message:  http.StatusText(http.StatusInternalServerError),
+						Internal: fmt.Errorf("bolo.authenticationMiddleware error on authenticate with %s: %w", strategy.GetName(), err),
+					}
+				}
+
+				if user == nil {
+					continue
+				}
+
+				if user.IsBlocked() || !user.IsActive() {
+					logrus.WithFields(logrus.Fields{
+						"strategy": strategy.GetName(),
+						"userID":   user.GetID(),
+					}).Debug("bolo.authenticationMiddleware user is blocked or inactive")
+					break
+				}
+
+				ctx.SetAuthenticatedUserAndFillRoles(user)
+				ctx.Set("authenticationStrategy", strategy.GetName())
+				break
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+func isInvalidCredentialsError(err error) bool {
+	return errors.Is(err, ErrInvalidToken) || errors.Is(err, ErrExpiredToken)
+}
+
+// Get the error of the routes that the request can't access, 401 for anonymous requests with invalid credentials
+func newPermissionDeniedError(ctx *RequestContext) error {
+	if !ctx.IsAuthenticated && ctx.GetAuthenticationError() != nil {
+		return &HTTPError{
+			Code:     http.StatusUnauthorized,
+			Message:  "Unauthorized",
+			Internal: ctx.GetAuthenticationError(),
+		}
+	}
+
+	return &HTTPError{
+		Code:    http.StatusForbidden,
+		Message: "Forbidden",
+	}
+}
+
+// Login - Authenticate the user in the current and next requests with the session.
+// The session id is regenerated to prevent session fixation
+func (r *RequestContext) Login(user UserInterface) error {
+	err := r.Session.Regenerate()
+	if err != nil {
+		return errors.Wrap(err, "bolo.RequestContext.Login error on regenerate session")
+	}
+
+	r.Session.Set(SessionUserIDKey, user.GetID())
+
+	r.SetAuthenticatedUserAndFillRoles(user)
+	r.Set("authenticationStrategy", "session")
+
+	return nil
+}
+
+// Logout - Remove the authenticated user from the current request and destroy the session
+func (r *RequestContext) Logout() error {
+	err := r.Session.Destroy()
+	if err != nil {
+		return errors.Wrap(err, "bolo.RequestContext.Logout error on destroy session")
+	}
+
+	r.AuthenticatedUser = nil
+	r.IsAuthenticated = false
+	r.Roles = []string{}
+
+	return nil
+}
+
+// GetAuthenticationError - Get the ErrInvalidToken or ErrExpiredToken error of requests with invalid credentials
+func (r *RequestContext) GetAuthenticationError() error {
+	err, _ := r.Get("authenticationError").(error)
+	return err
+}
+
+// GetAuthenticationStrategy - Get the name of the strategy used to authenticate the request or "" for unauthenticated requests
+func (r *RequestContext) GetAuthenticationStrategy() string {
+	return r.GetString("authenticationStrategy")
+}
@@ -0,0 +1,264 @@
+package bolo_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-bolo/bolo"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticationToken(t *testing.T) {
+	app := GetTestApp()
+
+	token, err := bolo.NewAuthenticationToken(app, "10", time.Hour)
+	assert.Nil(t, err)
+
+	claims, err := bolo.ParseAuthenticationToken(app, token)
+	assert.Nil(t, err)
+	assert.Equal(t, "10", claims.Subject)
+
+	_, err = bolo.ParseAuthenticationToken(app, token+"x")
+	assert.Equal(t, bolo.ErrInvalidToken, err)
+
+	expired, _ := bolo.NewAuthenticationToken(app, "10", -time.Minute)
+	_, err = bolo.ParseAuthenticationToken(app, expired)
+	assert.Equal(t, bolo.ErrExpiredToken, err)
+}
+
+func TestAuthenticationMiddleware(t *testing.T) {
+	app := GetTestApp()
+	app.SetUserLoader(bolo.NewUserLoaderFromModel(func() bolo.UserInterface {
+		return &UserModel{}
+	}))
+
+	err := app.Bootstrap()
+	assert.Nil(t, err)
+
+	app.GetRouter().GET("/me", func(c echo.Context) error {
+		ctx := c.(*bolo.RequestContext)
+		if !ctx.IsAuthenticated {
+			return c.String(http.StatusOK, "anonymous")
+		}
+
+		return c.String(http.StatusOK, ctx.AuthenticatedUser.GetID()+":"+ctx.GetAuthenticationStrategy())
+	})
+	app.SetRoute(app.GetRouterGroup("main"), &bolo.Route{
+		Method:     http.MethodGet,
+		Path:       "private",
+		Permission: "view_private",
+		Action: func(c echo.Context) error {
+			return c.String(http.StatusOK, "private")
+		},
+	})
+
+	validToken, _ := bolo.NewAuthenticationToken(app, "10", time.Hour)
+	blockedToken, _ := bolo.NewAuthenticationToken(app, "blocked", time.Hour)
+	missingToken, _ := bolo.NewAuthenticationToken(app, "missing", time.Hour)
+	errorToken, _ := bolo.NewAuthenticationToken(app, "error", time.Hour)
+
+	tests := []struct {
+		name           string
+		url            string
+		authorization  string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "should run as anonymous without credentials",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "anonymous",
+		},
+		{
+			name:           "should authenticate with a valid bearer token",
+			authorization:  "Bearer " + validToken,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "10:bearer",
+		},
+		{
+			name:           "should not authenticate blocked users",
+			authorization:  "Bearer " + blockedToken,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "anonymous",
+		},
+		{
+			name:           "should run public routes as anonymous with invalid bearer token",
+			authorization:  "Bearer invalid",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "anonymous",
+		},
+		{
+			name:           "should return 401 in routes with permission with invalid bearer token",
+			url:            "/private",
+			authorization:  "Bearer invalid",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "should return 403 in routes with permission without credentials",
+			url:            "/private",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "should return 401 in routes with permission with token of one user that doesn't exist",
+			url:            "/private",
+			authorization:  "Bearer " + missingToken,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "should return 500 if the user loader fails",
+			authorization:  "Bearer " + errorToken,
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := tt.url
+			if url == "" {
+				url = "/me"
+			}
+
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			req.Header.Set(echo.HeaderAccept, "application/json")
+			if tt.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tt.authorization)
+			}
+
+			rec := httptest.NewRecorder()
+			app.GetRouter().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuthenticationMiddleware_Session(t *testing.T) {
+	app := GetTestApp()
+	app.SetSessionStore(bolo.NewMemorySessionStore())
+	app.SetUserLoader(bolo.NewUserLoaderFromModel(func() bolo.UserInterface {
+		return &UserModel{}
+	}))
+
+	err := app.Bootstrap()
+	assert.Nil(t, err)
+
+	router := app.GetRouter()
+	router.POST("/login/:id", func(c echo.Context) error {
+		return c.(*bolo.RequestContext).Login(&UserModel{ID: c.Param("id"), Active: true})
+	})
+	router.GET("/me", func(c echo.Context) error {
+		ctx := c.(*bolo.RequestContext)
+		if !ctx.IsAuthenticated {
+			return c.String(http.StatusOK, "anonymous:"+ctx.Session.GetString(bolo.SessionUserIDKey))
+		}
+
+		return c.String(http.StatusOK, ctx.AuthenticatedUser.GetID()+":"+ctx.GetAuthenticationStrategy())
+	})
+
+	serve := func(method, url string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, nil)
+		req.Header.Set(echo.HeaderAccept, "application/json")
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("should authenticate with the session user", func(t *testing.T) {
+		cookies := serve(http.MethodPost, "/login/10", nil).Result().Cookies()
+
+		rec := serve(http.MethodGet, "/me", cookies)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "10:session", rec.Body.String())
+	})
+
+	t.Run("should remove users that don't exist from the session", func(t *testing.T) {
+		cookies := serve(http.MethodPost, "/login/missing", nil).Result().Cookies()
+
+		rec := serve(http.MethodGet, "/me", cookies)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "anonymous:", rec.Body.String())
+
+		rec = serve(http.MethodGet, "/me", cookies)
+		assert.Equal(t, "anonymous:", rec.Body.String())
+	})
+
+	t.Run("should return 500 if the user loader fails", func(t *testing.T) {
+		cookies := serve(http.MethodPost, "/login/error", nil).Result().Cookies()
+
+		rec := serve(http.MethodGet, "/me", cookies)
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
+
+func TestAPIKeyAuthenticationStrategy(t *testing.T) {
+	app := GetTestApp()
+	assert.Nil(t, app.AddAuthenticationStrategy(&bolo.APIKeyAuthenticationStrategy{
+		Loader: func(ctx *bolo.RequestContext, key string) (bolo.UserInterface, error) {
+			if key == "key-10" {
+				return &UserModel{ID: "10", Active: true}, nil
+			}
+
+			return nil, nil
+		},
+	}))
+	assert.Nil(t, app.Bootstrap())
+
+	app.GetRouter().GET("/me", func(c echo.Context) error {
+		ctx := c.(*bolo.RequestContext)
+		if !ctx.IsAuthenticated {
+			return c.String(http.StatusOK, "anonymous")
+		}
+
+		return c.String(http.StatusOK, ctx.AuthenticatedUser.GetID()+":"+ctx.GetAuthenticationStrategy())
+	})
+	app.SetRoute(app.GetRouterGroup("main"), &bolo.Route{
+		Method:     http.MethodGet,
+		Path:       "private",
+		Permission: "view_private",
+		Action: func(c echo.Context) error {
+			return c.String(http.StatusOK, "private")
+		},
+	})
+
+	serve := func(url, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		req.Header.Set(echo.HeaderAccept, "application/json")
+		req.Header.Set("X-API-Key", key)
+		rec := httptest.NewRecorder()
+		app.GetRouter().ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("/me", "key-10")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "10:apiKey", rec.Body.String())
+
+	rec = serve("/me", "invalid")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "anonymous", rec.Body.String())
+
+	assert.Equal(t, http.StatusUnauthorized, serve("/private", "invalid").Code)
+
+	t.Run("should ignore the key without Loader", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("X-API-Key", "key-10")
+		ctx := bolo.NewRequestContext(&bolo.RequestContextOpts{
+			App:         app,
+			EchoContext: app.GetRouter().NewContext(req, httptest.NewRecorder()),
+		})
+
+		strategy := bolo.APIKeyAuthenticationStrategy{}
+		user, err := strategy.Authenticate(ctx)
+		assert.Nil(t, err)
+		assert.Nil(t, user)
+	})
+}
@@ -0,0 +1,78 @@
+package bolo
+
+import (
+	"crypto/hmac"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid authentication token")
+	ErrExpiredToken = errors.New("expired authentication token")
+)
+
+// AuthenticationTokenClaims - JWT claims used in bearer authentication tokens
+type AuthenticationTokenClaims struct {
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// NewAuthenticationToken - Create a HS256 JWT token for the user signed with the app secret
+func NewAuthenticationToken(app App, userID string, ttl time.Duration) (string, error) {
+	now := app.GetClock().Now()
+
+	claims := AuthenticationTokenClaims{
+		Subject:   userID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	}
+
+	payload, err := json.Marshal(&claims)
+	if err != nil {
+		return "", errors.Wrap(err, "bolo.NewAuthenticationToken error on marshal claims")
+	}
+
+	unsigned := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	return unsigned + "." + signatureOf(app.GetSecret(), unsigned), nil
+}
+
+// ParseAuthenticationToken - Validate one token created with NewAuthenticationToken and return its claims
+func ParseAuthenticationToken(app App, token string) (*AuthenticationTokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != jwtHeader {
+		return nil, ErrInvalidToken
+	}
+
+	unsigned := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(signatureOf(app.GetSecret(), unsigned))) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	claims := AuthenticationTokenClaims{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+
+	if claims.ExpiresAt <= app.GetClock().Now().Unix() {
+		return nil, ErrExpiredToken
+	}
+
+	return &claims, nil
+}
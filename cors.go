@@ -0,0 +1,179 @@
+package bolo
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-bolo/bolo/configuration"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/sirupsen/logrus"
+)
+
+// CORSOptions - CORS policy for one router group
+type CORSOptions struct {
+	// Allowed origins as exact values (https://example.com), wildcard subdomains (https://*.example.com or *.example.com) or *.
+	// Wildcard subdomains without port allow all ports, ex: https://*.example.com:8443 allows only the 8443 port
+	AllowOrigins     []string
+	AllowMethods     []string
+	AllowHeaders     []string
+	ExposeHeaders    []string
+	AllowCredentials bool
+	// Preflight cache time in seconds
+	MaxAge int
+}
+
+// IsOriginAllowed - Check if the origin matches one of the allowed origins
+func (o *CORSOptions) IsOriginAllowed(origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+
+	for _, allowed := range o.AllowOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+
+		scheme, host, found := strings.Cut(allowed, "://")
+		if !found {
+			host = scheme
+			scheme = ""
+		}
+
+		if scheme != "" && scheme != u.Scheme {
+			continue
+		}
+
+		if strings.HasPrefix(host, "*.") {
+			// wildcard subdomains without port match origins in any port
+			suffix, port, hasPort := strings.Cut(host[1:], ":")
+			if strings.HasSuffix(u.Hostname(), suffix) && (!hasPort || port == u.Port()) {
+				return true
+			}
+			continue
+		}
+
+		if host == u.Host {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewCORSOptionsFromConfiguration - Build CORS options from configuration.
+// With a router group name the CORS_[GROUP]_* variables override the global CORS_* variables, ex: CORS_API_ALLOWED_ORIGINS
+func NewCORSOptionsFromConfiguration(cfg configuration.ConfigurationInterface, groupName string) *CORSOptions {
+	get := func(key, fallback string) string {
+		value := cfg.GetF("CORS_"+key, fallback)
+		if groupName != "" {
+			value = cfg.GetF("CORS_"+configurationKeyName(groupName)+"_"+key, value)
+		}
+
+		return value
+	}
+
+	port := cfg.GetF("PORT", "8080")
+	protocol := cfg.GetF("PROTOCOL", "http")
+	domain := cfg.GetF("DOMAIN", "localhost")
+	appOrigin := cfg.GetF("APP_ORIGIN", protocol+"://"+domain+":"+port)
+
+	allowCredentials, err := strconv.ParseBool(get("ALLOW_CREDENTIALS", "true"))
+	if err != nil {
+		allowCredentials = false
+	}
+	maxAge := cfg.GetIntF("CORS_MAX_AGE", 18000) // seccounds
+	if groupName != "" {
+		maxAge = cfg.GetIntF("CORS_"+configurationKeyName(groupName)+"_MAX_AGE", maxAge)
+	}
+
+	return &CORSOptions{
+		AllowOrigins:     splitConfigurationList(get("ALLOWED_ORIGINS", appOrigin)),
+		AllowMethods:     splitConfigurationList(get("ALLOWED_METHODS", "GET,HEAD,PUT,PATCH,POST,DELETE")),
+		AllowHeaders:     splitConfigurationList(get("ALLOWED_HEADERS", "")),
+		ExposeHeaders:    splitConfigurationList(get("EXPOSED_HEADERS", "")),
+		AllowCredentials: allowCredentials,
+		MaxAge:           maxAge,
+	}
+}
+
+// Transform one name like url-api in a configuration key like URL_API
+func configurationKeyName(name string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_", " ", "_").Replace(name))
+}
+
+func splitConfigurationList(value string) []string {
+	list := []string{}
+	for _, v := range strings.Split(value, ",") {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			list = append(list, v)
+		}
+	}
+
+	return list
+}
+
+func newCORSMiddleware(groupName string, opts *CORSOptions) echo.MiddlewareFunc {
+	if opts.AllowCredentials && len(opts.AllowOrigins) == 1 && opts.AllowOrigins[0] == "*" {
+		logrus.WithFields(logrus.Fields{
+			"routerGroup": groupName,
+		}).Warn("bolo.newCORSMiddleware all origins are allowed with credentials, set CORS_ALLOWED_ORIGINS")
+	}
+
+	return middleware.CORSWithConfig(middleware.CORSConfig{
+		AllowMethods:     opts.AllowMethods,
+		AllowHeaders:     opts.AllowHeaders,
+		ExposeHeaders:    opts.ExposeHeaders,
+		AllowCredentials: opts.AllowCredentials,
+		MaxAge:           opts.MaxAge,
+		AllowOriginFunc: func(origin string) (bool, error) {
+			if opts.IsOriginAllowed(origin) {
+				return true, nil
+			}
+
+			logrus.WithFields(logrus.Fields{
+				"origin":      origin,
+				"routerGroup": groupName,
+			}).Info("bolo.corsMiddleware origin not allowed")
+
+			return false, nil
+		},
+	})
+}
+
+// Middleware that applies the CORS policy of the router group that matches the request path
+func corsMiddleware(app App) echo.MiddlewareFunc {
+	var handlers sync.Map
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().Header.Get(echo.HeaderOrigin) == "" {
+				return next(c)
+			}
+
+			groupName := app.GetRouterGroupNameByPath(c.Request().URL.Path)
+
+			h, ok := handlers.Load(groupName)
+			if !ok {
+				opts := app.GetRouterGroupCORS(groupName)
+				if opts == nil {
+					opts = NewCORSOptionsFromConfiguration(app.GetConfiguration(), groupName)
+				}
+
+				h, _ = handlers.LoadOrStore(groupName, newCORSMiddleware(groupName, opts))
+			}
+
+			return h.(echo.MiddlewareFunc)(next)(c)
+		}
+	}
+}
+
+// Check if one request path is inside one router group path
+func isPathInRouterGroup(path, groupPath string) bool {
+	groupPath = strings.TrimSuffix(groupPath, "/")
+	return groupPath == "" || path == groupPath || strings.HasPrefix(path, groupPath+"/")
+}
@@ -0,0 +1,140 @@
+package bolo_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/go-bolo/bolo"
+	"github.com/go-bolo/bolo/configuration"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORSOptions_IsOriginAllowed(t *testing.T) {
+	opts := bolo.CORSOptions{
+		AllowOrigins: []string{"https://www.example.com", "https://*.example.org", "*.example.net", "http://*.example.dev:3000", "http://localhost:8080"},
+	}
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"https://www.example.com", true},
+		{"http://www.example.com", false},
+		{"https://evil.com", false},
+		{"https://api.example.org", true},
+		{"http://api.example.org", false},
+		{"https://example.org", false},
+		{"https://example.org.evil.com", false},
+		{"http://cdn.example.net", true},
+		{"https://api.example.org:8443", true},
+		{"http://cdn.example.net:3000", true},
+		{"https://example.org.evil.com:8443", false},
+		{"http://app.example.dev:3000", true},
+		{"http://app.example.dev:3001", false},
+		{"http://app.example.dev", false},
+		{"http://localhost:8080", true},
+		{"http://localhost:3000", false},
+		{"null", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			assert.Equal(t, tt.want, opts.IsOriginAllowed(tt.origin))
+		})
+	}
+}
+
+// Configuration with values that are not environment variables
+type corsTestConfiguration struct {
+	configuration.ConfigurationInterface
+	values map[string]string
+}
+
+func (c *corsTestConfiguration) GetF(key, fallback string) string {
+	if v, ok := c.values[key]; ok {
+		return v
+	}
+	return fallback
+}
+
+func (c *corsTestConfiguration) GetIntF(key string, fallback int) int {
+	if v, ok := c.values[key]; ok {
+		i, _ := strconv.Atoi(v)
+		return i
+	}
+	return fallback
+}
+
+func TestNewCORSOptionsFromConfiguration(t *testing.T) {
+	cfg := &corsTestConfiguration{
+		ConfigurationInterface: configuration.NewCfg(),
+		values: map[string]string{
+			"CORS_ALLOWED_ORIGINS": "https://www.example.com",
+			"CORS_MAX_AGE":         "600",
+			"CORS_API_MAX_AGE":     "60",
+		},
+	}
+
+	opts := bolo.NewCORSOptionsFromConfiguration(cfg, "")
+	assert.Equal(t, []string{"https://www.example.com"}, opts.AllowOrigins)
+	assert.Equal(t, 600, opts.MaxAge)
+
+	assert.Equal(t, 60, bolo.NewCORSOptionsFromConfiguration(cfg, "api").MaxAge)
+	assert.Equal(t, 600, bolo.NewCORSOptionsFromConfiguration(cfg, "admin").MaxAge)
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	os.Setenv("CORS_ALLOWED_ORIGINS", "https://www.example.com")
+	os.Setenv("CORS_API_ALLOWED_ORIGINS", "https://*.example.com")
+	defer os.Unsetenv("CORS_ALLOWED_ORIGINS")
+	defer os.Unsetenv("CORS_API_ALLOWED_ORIGINS")
+
+	app := GetTestApp()
+	err := app.Bootstrap()
+	assert.Nil(t, err)
+
+	app.GetRouterGroup("api").GET("/ping", func(c echo.Context) error {
+		return c.String(http.StatusOK, "pong")
+	})
+	app.GetRouter().GET("/ping", func(c echo.Context) error {
+		return c.String(http.StatusOK, "pong")
+	})
+
+	tests := []struct {
+		name          string
+		url           string
+		origin        string
+		expectedAllow string
+	}{
+		{
+			name:          "should allow configured origins",
+			url:           "/ping",
+			origin:        "https://www.example.com",
+			expectedAllow: "https://www.example.com",
+		},
+		{
+			name:   "should not allow other origins",
+			url:    "/ping",
+			origin: "https://app.example.com",
+		},
+		{
+			name:          "should use the router group override",
+			url:           "/api/ping",
+			origin:        "https://app.example.com",
+			expectedAllow: "https://app.example.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			req.Header.Set(echo.HeaderOrigin, tt.origin)
+			rec := httptest.NewRecorder()
+			app.GetRouter().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedAllow, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package bolo
+
+import (
+	"crypto/subtle"
+	"html/template"
+	"mime"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Session key where the CSRF token is stored
+	SessionCSRFTokenKey = "csrfToken"
+	// Form field name used by the csrfField template function
+	CSRFFormField = "_csrf"
+	CSRFHeader    = "X-CSRF-Token"
+)
+
+// GetCSRFToken - Get the session CSRF token, creating it if needed
+func (r *RequestContext) GetCSRFToken() string {
+	token := r.Session.GetString(SessionCSRFTokenKey)
+	if token == "" {
+		token = RandomToken(32)
+		r.Session.Set(SessionCSRFTokenKey, token)
+	}
+
+	return token
+}
+
+// Check if the request should be validated by the CSRF middleware
+func requiresCSRFValidation(ctx *RequestContext) bool {
+	if !isUnsafeMethod(ctx.Request().Method) {
+		return false
+	}
+
+	// requests authenticated without cookies can't be forged by other sites
+	switch ctx.GetAuthenticationStrategy() {
+	case "bearer", "apiKey":
+		return false
+	}
+
+	route := ctx.App.GetRoute(ctx.Request().Method, ctx.Path())
+	if route != nil && route.SkipCSRF {
+		return false
+	}
+
+	if ctx.GetResponseContentType() == "text/html" {
+		return true
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(ctx.Request().Header.Get(echo.HeaderContentType))
+	switch mediaType {
+	case echo.MIMEApplicationForm, echo.MIMEMultipartForm, "text/plain":
+		return true
+	}
+
+	return false
+}
+
+// Middleware that validates the CSRF token in unsafe HTML and form requests
+func csrfMiddleware(app App) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.(*RequestContext)
+
+			if !requiresCSRFValidation(ctx) {
+				return next(ctx)
+			}
+
+			token := ctx.Request().Header.Get(CSRFHeader)
+			if token == "" {
+				token = ctx.FormValue(CSRFFormField)
+			}
+
+			expected := ctx.Session.GetString(SessionCSRFTokenKey)
+			if token == "" || expected == "" || subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+				logrus.WithFields(logrus.Fields{
+					"path":   c.Path(),
+					"method": c.Request().Method,
+				}).Info("bolo.csrfMiddleware invalid CSRF token")
+
+				return &HTTPError{
+					Code:    http.StatusForbidden,
+					Message: "Invalid CSRF token",
+				}
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+func csrfToken(ctx *RequestContext) string {
+	return ctx.GetCSRFToken()
+}
+
+func csrfField(ctx *RequestContext) template.HTML {
+	return template.HTML(`<input type="hidden" name="` + CSRFFormField + `" value="` + template.HTMLEscapeString(ctx.GetCSRFToken()) + `">`)
+}
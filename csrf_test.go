@@ -0,0 +1,120 @@
+package bolo_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-bolo/bolo"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSRF(t *testing.T) {
+	app := GetTestApp()
+	app.SetSessionStore(bolo.NewMemorySessionStore())
+	app.SetUserLoader(bolo.NewUserLoaderFromModel(func() bolo.UserInterface {
+		return &UserModel{}
+	}))
+
+	err := app.Bootstrap()
+	assert.Nil(t, err)
+
+	router := app.GetRouter()
+	router.GET("/form", func(c echo.Context) error {
+		return c.String(http.StatusOK, c.(*bolo.RequestContext).GetCSRFToken())
+	})
+	router.POST("/form", func(c echo.Context) error {
+		return c.String(http.StatusOK, "saved")
+	})
+	app.SetRoute(app.GetRouterGroup("main"), &bolo.Route{
+		Method:   http.MethodPost,
+		Path:     "webhook",
+		SkipCSRF: true,
+		Action: func(c echo.Context) error {
+			return c.String(http.StatusOK, "received")
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/form", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	token := rec.Body.String()
+	cookies := rec.Result().Cookies()
+	assert.Len(t, cookies, 1)
+
+	bearer, _ := bolo.NewAuthenticationToken(app, "10", time.Hour)
+
+	tests := []struct {
+		name           string
+		url            string
+		token          string
+		authorization  string
+		contentType    string
+		expectedStatus int
+	}{
+		{
+			name:           "should reject form posts without token",
+			url:            "/form",
+			contentType:    echo.MIMEApplicationForm,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "should reject form posts with invalid token",
+			url:            "/form",
+			token:          "invalid",
+			contentType:    echo.MIMEApplicationForm,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "should accept form posts with the session token",
+			url:            "/form",
+			token:          token,
+			contentType:    echo.MIMEApplicationForm,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "should skip JSON requests",
+			url:            "/form",
+			contentType:    echo.MIMEApplicationJSON,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "should skip requests authenticated with bearer tokens",
+			url:            "/form",
+			authorization:  "Bearer " + bearer,
+			contentType:    echo.MIMEApplicationForm,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "should skip routes with SkipCSRF",
+			url:            "/webhook",
+			contentType:    echo.MIMEApplicationForm,
+			expectedStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := url.Values{}
+			if tt.token != "" {
+				form.Set(bolo.CSRFFormField, tt.token)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, tt.url, strings.NewReader(form.Encode()))
+			req.Header.Set(echo.HeaderContentType, tt.contentType)
+			req.Header.Set(echo.HeaderAccept, "application/json")
+			if tt.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tt.authorization)
+			}
+			req.AddCookie(cookies[0])
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
@@ -4,50 +4,400 @@ import (
 	"bytes"
 	"context"
 	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
 )
 
+// SRID used in points without SRID. The default 0 keeps the legacy MySQL layout, POINT(lat lng) without spatial
+// reference, compatible with the columns created by the previous versions.
+// Set it to 4326 (WGS 84 / GPS coordinates) to store MySQL points in the standard longitude, latitude order,
+// this requires MySQL >= 8.0.12 and the migration of the existing rows and columns, ex:
+//
+//	UPDATE places SET location = ST_SRID(POINT(ST_Y(location), ST_X(location)), 4326);
+//	ALTER TABLE places MODIFY location POINT NOT NULL SRID 4326;
+var DefaultSRID = 0
+
+// EWKB flag of geometries with SRID
+const ewkbSRIDFlag = 0x20000000
+
+// GeoPoint - Point with X as latitude and Y as longitude.
+// Encoded in WKB, WKT and GeoJSON in the standard longitude, latitude order.
+// MySQL stores it in one geometry column, Postgres in one point column and SQLite in one text column with the EWKT.
+// SQLite don't have spatial types, for distance queries embed the point as two float columns:
+//
+//	Location database.GeoPoint `gorm:"embedded;embeddedPrefix:location_"`
 type GeoPoint struct {
-	X, Y float64
+	X float64 `gorm:"column:lat"`
+	Y float64 `gorm:"column:lng"`
+	// Spatial reference id, 0 uses the DefaultSRID
+	SRID int `gorm:"-"`
+}
+
+func NewGeoPoint(lat, lng float64) GeoPoint {
+	return GeoPoint{X: lat, Y: lng}
+}
+
+func (loc GeoPoint) Lat() float64 {
+	return loc.X
+}
+
+func (loc GeoPoint) Lng() float64 {
+	return loc.Y
+}
+
+// GetSRID - Get the point SRID or the DefaultSRID
+func (loc GeoPoint) GetSRID() int {
+	if loc.SRID != 0 {
+		return loc.SRID
+	}
+
+	return DefaultSRID
 }
 
 func (loc GeoPoint) GormDataType() string {
 	return "geometry"
 }
 
+// GormDBDataType - Postgres uses the native point type, without PostGIS, and SQLite one text column
+func (loc GeoPoint) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	if _, ok := field.TagSettings["TYPE"]; ok {
+		return ""
+	}
+
+	switch db.Dialector.Name() {
+	case "postgres":
+		return "point"
+	case "sqlite":
+		return "text"
+	default:
+		return "geometry"
+	}
+}
+
 func (loc GeoPoint) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	switch db.Dialector.Name() {
+	case "postgres":
+		return clause.Expr{
+			SQL:  "point(?, ?)",
+			Vars: []interface{}{loc.Y, loc.X},
+		}
+	case "sqlite":
+		return clause.Expr{
+			SQL:  "?",
+			Vars: []interface{}{loc.EWKT()},
+		}
+	default:
+		return mysqlGeomFromText(loc)
+	}
+}
+
+func mysqlGeomFromText(loc GeoPoint) clause.Expr {
+	srid := loc.GetSRID()
+	if srid == 0 {
+		// legacy layout, with the latitude as the first coordinate
+		return clause.Expr{
+			SQL:  "ST_GeomFromText(?)",
+			Vars: []interface{}{"POINT(" + formatCoordinate(loc.X) + " " + formatCoordinate(loc.Y) + ")"},
+		}
+	}
+
 	return clause.Expr{
-		SQL:  "GeomFromText(?)",
-		Vars: []interface{}{fmt.Sprintf("POINT(%f %f)", loc.X, loc.Y)},
+		SQL:  "ST_GeomFromText(?, ?, 'axis-order=long-lat')",
+		Vars: []interface{}{loc.WKT(), srid},
 	}
 }
 
+// Scan - Read the point from the MySQL internal format, WKB, EWKB, hex EWKB, WKT, EWKT or one Postgres point
 func (loc *GeoPoint) Scan(src interface{}) error {
 	switch b := src.(type) {
+	case string:
+		return loc.scanText(b)
 	case []byte:
-		if len(b) != 25 {
-			return fmt.Errorf("expected []bytes with length 25, got %d", len(b))
+		if len(b) > 0 && (b[0] == '(' || b[0] == 'P' || b[0] == 'p' || b[0] == 'S' || b[0] == 's' || isHex(string(b))) {
+			return loc.scanText(string(b))
+		}
+
+		// MySQL internal format: 4 bytes SRID + WKB
+		if len(b) == 25 && !isEWKB(b) {
+			srid := binary.LittleEndian.Uint32(b[:4])
+			err := loc.UnmarshalWKB(b[4:])
+			if err != nil {
+				return err
+			}
+			loc.SRID = int(srid)
+
+			// points without SRID use the legacy layout, with the latitude as the first coordinate
+			if srid == 0 {
+				loc.X, loc.Y = loc.Y, loc.X
+			}
+
+			return nil
 		}
-		var latitude float64
-		var longitude float64
-		buf := bytes.NewReader(b[9:17])
-		err := binary.Read(buf, binary.LittleEndian, &latitude)
+
+		return loc.UnmarshalWKB(b)
+	case nil:
+		*loc = GeoPoint{}
+		return nil
+	default:
+		return fmt.Errorf("expected []byte or string for GeoPoint type, got %T", src)
+	}
+}
+
+func (loc *GeoPoint) scanText(src string) error {
+	src = strings.TrimSpace(src)
+
+	switch {
+	case strings.HasPrefix(src, "("):
+		return loc.scanPostgresPoint(src)
+	case isHex(src):
+		// PostGIS returns geometries as hex EWKB
+		b, err := hex.DecodeString(src)
 		if err != nil {
 			return err
 		}
-		buf = bytes.NewReader(b[17:25])
-		err = binary.Read(buf, binary.LittleEndian, &longitude)
+		return loc.UnmarshalWKB(b)
+	default:
+		return loc.UnmarshalWKT(src)
+	}
+}
+
+// postgres point: (x,y)
+func (loc *GeoPoint) scanPostgresPoint(src string) error {
+	var lng, lat float64
+	_, err := fmt.Sscanf(strings.ReplaceAll(src, " ", ""), "(%g,%g)", &lng, &lat)
+	if err != nil {
+		return fmt.Errorf("invalid postgres point %q: %w", src, err)
+	}
+
+	loc.X = lat
+	loc.Y = lng
+
+	return nil
+}
+
+func isEWKB(b []byte) bool {
+	switch b[0] {
+	case 0:
+		return binary.BigEndian.Uint32(b[1:5])&ewkbSRIDFlag != 0
+	case 1:
+		return binary.LittleEndian.Uint32(b[1:5])&ewkbSRIDFlag != 0
+	default:
+		return false
+	}
+}
+
+func isHex(src string) bool {
+	if len(src) == 0 || len(src)%2 != 0 {
+		return false
+	}
+
+	for _, c := range src {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// WKT - Format the point as OGC well known text, ex: POINT(-48.548 -27.5954)
+func (loc GeoPoint) WKT() string {
+	return "POINT(" + formatCoordinate(loc.Y) + " " + formatCoordinate(loc.X) + ")"
+}
+
+// EWKT - Format the point as extended well known text with the SRID, ex: SRID=4326;POINT(-48.548 -27.5954)
+func (loc GeoPoint) EWKT() string {
+	srid := loc.GetSRID()
+	if srid == 0 {
+		return loc.WKT()
+	}
+
+	return "SRID=" + strconv.Itoa(srid) + ";" + loc.WKT()
+}
+
+// UnmarshalWKT - Parse one WKT or EWKT point
+func (loc *GeoPoint) UnmarshalWKT(src string) error {
+	src = strings.TrimSpace(src)
+	srid := 0
+
+	if strings.HasPrefix(strings.ToUpper(src), "SRID=") {
+		prefix, rest, ok := strings.Cut(src[5:], ";")
+		if !ok {
+			return fmt.Errorf("invalid EWKT point %q", src)
+		}
+
+		v, err := strconv.Atoi(prefix)
 		if err != nil {
-			return err
+			return fmt.Errorf("invalid EWKT point SRID %q: %w", src, err)
 		}
+		srid = v
+		src = strings.TrimSpace(rest)
+	}
+
+	upper := strings.ToUpper(src)
+	if !strings.HasPrefix(upper, "POINT") {
+		return fmt.Errorf("invalid WKT point %q", src)
+	}
+
+	body := strings.TrimSpace(src[5:])
+	if !strings.HasPrefix(body, "(") || !strings.HasSuffix(body, ")") {
+		return fmt.Errorf("invalid WKT point %q", src)
+	}
+
+	coordinates := strings.Fields(body[1 : len(body)-1])
+	if len(coordinates) != 2 {
+		return fmt.Errorf("invalid WKT point %q", src)
+	}
+
+	lng, err := strconv.ParseFloat(coordinates[0], 64)
+	if err != nil {
+		return fmt.Errorf("invalid WKT point %q: %w", src, err)
+	}
+	lat, err := strconv.ParseFloat(coordinates[1], 64)
+	if err != nil {
+		return fmt.Errorf("invalid WKT point %q: %w", src, err)
+	}
+
+	loc.X = lat
+	loc.Y = lng
+	loc.SRID = srid
+
+	return nil
+}
+
+// MarshalWKB - Encode the point as little endian WKB, or EWKB if the point has one SRID
+func (loc GeoPoint) MarshalWKB() []byte {
+	buf := bytes.Buffer{}
+	buf.WriteByte(1)
+
+	if loc.SRID != 0 {
+		binary.Write(&buf, binary.LittleEndian, uint32(1|ewkbSRIDFlag))
+		binary.Write(&buf, binary.LittleEndian, uint32(loc.SRID))
+	} else {
+		binary.Write(&buf, binary.LittleEndian, uint32(1))
+	}
+
+	binary.Write(&buf, binary.LittleEndian, loc.Y)
+	binary.Write(&buf, binary.LittleEndian, loc.X)
+
+	return buf.Bytes()
+}
 
-		loc.X = latitude
-		loc.Y = longitude
+// UnmarshalWKB - Decode one WKB or EWKB point, in big or little endian
+func (loc *GeoPoint) UnmarshalWKB(b []byte) error {
+	if len(b) < 21 {
+		return fmt.Errorf("invalid WKB point, expected at least 21 bytes, got %d", len(b))
+	}
+
+	var order binary.ByteOrder
+	switch b[0] {
+	case 0:
+		order = binary.BigEndian
+	case 1:
+		order = binary.LittleEndian
 	default:
-		return fmt.Errorf("expected []byte for Location type, got  %T", src)
+		return fmt.Errorf("invalid WKB byte order %d", b[0])
+	}
+
+	geometryType := order.Uint32(b[1:5])
+	srid := 0
+	offset := 5
+
+	if geometryType&ewkbSRIDFlag != 0 {
+		if len(b) < 25 {
+			return fmt.Errorf("invalid EWKB point, expected 25 bytes, got %d", len(b))
+		}
+		srid = int(order.Uint32(b[5:9]))
+		offset = 9
+	}
+
+	if geometryType&0xffff != 1 {
+		return fmt.Errorf("invalid WKB geometry type %d, expected one point", geometryType&0xffff)
 	}
+
+	loc.Y = math.Float64frombits(order.Uint64(b[offset : offset+8]))
+	loc.X = math.Float64frombits(order.Uint64(b[offset+8 : offset+16]))
+	loc.SRID = srid
+
+	return nil
+}
+
+type geoJSONPoint struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// MarshalGeoJSON - Encode the point as one GeoJSON Point geometry
+func (loc GeoPoint) MarshalGeoJSON() ([]byte, error) {
+	return json.Marshal(&geoJSONPoint{
+		Type:        "Point",
+		Coordinates: []float64{loc.Y, loc.X},
+	})
+}
+
+// UnmarshalGeoJSON - Decode one GeoJSON Point geometry
+func (loc *GeoPoint) UnmarshalGeoJSON(data []byte) error {
+	p := geoJSONPoint{}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+
+	if p.Type != "Point" || len(p.Coordinates) < 2 {
+		return fmt.Errorf("invalid GeoJSON point")
+	}
+
+	loc.Y = p.Coordinates[0]
+	loc.X = p.Coordinates[1]
+
+	return nil
+}
+
+type latLng struct {
+	Lat *float64 `json:"lat"`
+	Lng *float64 `json:"lng"`
+}
+
+// MarshalJSON - Encode the point as {"lat": X, "lng": Y}
+func (loc GeoPoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]float64{
+		"lat": loc.X,
+		"lng": loc.Y,
+	})
+}
+
+// UnmarshalJSON - Decode one {"lat", "lng"} object or one GeoJSON Point
+func (loc *GeoPoint) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	if bytes.Contains(data, []byte(`"coordinates"`)) {
+		return loc.UnmarshalGeoJSON(data)
+	}
+
+	v := latLng{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	if v.Lat == nil || v.Lng == nil {
+		return fmt.Errorf("invalid GeoPoint, lat and lng are required")
+	}
+
+	loc.X = *v.Lat
+	loc.Y = *v.Lng
+
 	return nil
 }
+
+func formatCoordinate(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
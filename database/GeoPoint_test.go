@@ -0,0 +1,112 @@
+package database_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-bolo/bolo/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeoPoint_Scan(t *testing.T) {
+	florianopolis := database.GeoPoint{X: -27.5954, Y: -48.548}
+
+	tests := []struct {
+		name    string
+		src     interface{}
+		want    database.GeoPoint
+		wantErr bool
+	}{
+		{name: "postgres point string", src: "(-48.548,-27.5954)", want: florianopolis},
+		{name: "postgres point bytes", src: []byte("(2, 1.5)"), want: database.GeoPoint{X: 1.5, Y: 2}},
+		{name: "wkt", src: "POINT(-48.548 -27.5954)", want: florianopolis},
+		{name: "ewkt", src: []byte("SRID=4326;POINT(-48.548 -27.5954)"), want: database.GeoPoint{X: -27.5954, Y: -48.548, SRID: 4326}},
+		{
+			name: "mysql internal format",
+			src: []byte{
+				0xe6, 0x10, 0x00, 0x00, // SRID 4326
+				0x01, 0x01, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x24, 0x40, // 10
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x34, 0x40, // 20
+			},
+			want: database.GeoPoint{X: 20, Y: 10, SRID: 4326},
+		},
+		{
+			name: "mysql legacy internal format without srid",
+			src: []byte{
+				0x00, 0x00, 0x00, 0x00, // SRID 0
+				0x01, 0x01, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x24, 0x40, // lat 10
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x34, 0x40, // lng 20
+			},
+			want: database.GeoPoint{X: 10, Y: 20},
+		},
+		{
+			name: "big endian wkb",
+			src: []byte{
+				0x00, 0x00, 0x00, 0x00, 0x01,
+				0x40, 0x24, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // 10
+				0x40, 0x34, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // 20
+			},
+			want: database.GeoPoint{X: 20, Y: 10},
+		},
+		{name: "postgis hex ewkb", src: "0101000020E610000000000000000024400000000000003440", want: database.GeoPoint{X: 20, Y: 10, SRID: 4326}},
+		{name: "null", src: nil, want: database.GeoPoint{}},
+		{name: "invalid point", src: "(a,b)", wantErr: true},
+		{name: "invalid wkt", src: "LINESTRING(1 2, 3 4)", wantErr: true},
+		{name: "invalid wkb", src: []byte{0x01, 0x02}, wantErr: true},
+		{name: "invalid type", src: 10, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := database.GeoPoint{}
+			err := p.Scan(tt.src)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, p)
+		})
+	}
+}
+
+func TestGeoPoint_Encoding(t *testing.T) {
+	p := database.GeoPoint{X: -27.5954, Y: -48.548, SRID: 4326}
+
+	t.Run("wkt", func(t *testing.T) {
+		assert.Equal(t, "POINT(-48.548 -27.5954)", p.WKT())
+		assert.Equal(t, "SRID=4326;POINT(-48.548 -27.5954)", p.EWKT())
+	})
+
+	t.Run("wkb should round trip with the srid", func(t *testing.T) {
+		decoded := database.GeoPoint{}
+		assert.Nil(t, decoded.UnmarshalWKB(p.MarshalWKB()))
+		assert.Equal(t, p, decoded)
+
+		withoutSRID := database.NewGeoPoint(1, 2)
+		assert.Len(t, withoutSRID.MarshalWKB(), 21)
+	})
+
+	t.Run("geojson should use the longitude first", func(t *testing.T) {
+		data, err := p.MarshalGeoJSON()
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{"type":"Point","coordinates":[-48.548,-27.5954]}`, string(data))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		data, err := json.Marshal(p)
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{"lat":-27.5954,"lng":-48.548}`, string(data))
+
+		decoded := database.GeoPoint{}
+		assert.Nil(t, json.Unmarshal([]byte(`{"lat":1.5,"lng":2}`), &decoded))
+		assert.Equal(t, database.NewGeoPoint(1.5, 2), decoded)
+
+		assert.Nil(t, json.Unmarshal([]byte(`{"type":"Point","coordinates":[2,1.5]}`), &decoded))
+		assert.Equal(t, database.NewGeoPoint(1.5, 2), decoded)
+
+		assert.NotNil(t, json.Unmarshal([]byte(`{"lat":1.5}`), &decoded))
+	})
+}
@@ -0,0 +1,213 @@
+package database
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/go-bolo/query_parser_to_db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Mean earth radius in meters
+const EarthRadius = 6371008.8
+
+// Meters in one degree of latitude
+const metersPerDegree = EarthRadius * math.Pi / 180
+
+var ErrGeoDistanceNotSupported = errors.New("database: distance queries in sqlite require one GeoPoint embedded as two float columns")
+
+// GeoQuery - Filter and sort records by the distance to one point, see RequestContext.GetGeoQuery
+type GeoQuery struct {
+	Center GeoPoint
+	// Max distance in meters, 0 to not filter
+	Radius          float64
+	OrderByDistance bool
+}
+
+// ParseGeoQuery - Parse the center as one "lat,lng" pair and the radius in meters, empty radius does not filter
+func ParseGeoQuery(center, radius string, orderByDistance bool) (*GeoQuery, error) {
+	latText, lngText, ok := strings.Cut(center, ",")
+	if !ok {
+		return nil, errors.New("near must be one lat,lng pair")
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latText), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return nil, errors.New("invalid near latitude")
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(lngText), 64)
+	if err != nil || lng < -180 || lng > 180 {
+		return nil, errors.New("invalid near longitude")
+	}
+
+	q := GeoQuery{
+		Center:          NewGeoPoint(lat, lng),
+		OrderByDistance: orderByDistance,
+	}
+
+	if radius != "" {
+		q.Radius, err = strconv.ParseFloat(radius, 64)
+		if err != nil || q.Radius <= 0 {
+			return nil, errors.New("radius must be one positive number of meters")
+		}
+	}
+
+	return &q, nil
+}
+
+// RegisterGeoFilter - Register the geo filter type in the query parser, the filter param is the GeoPoint column:
+//
+//	Location database.GeoPoint `filter:"param:location;type:geo"`
+//
+// and the query ?location=-27.59,-48.54&radius=1000&sort=distance filters and sorts by the distance. Called by bolo.NewApp
+func RegisterGeoFilter() {
+	query_parser_to_db.GORMDBAdapter["geo"] = query_parser_to_db.DBOperations{
+		"equal": func(column, value string, dbQuery interface{}, q query_parser_to_db.QueryInterface) (interface{}, error) {
+			gq, err := ParseGeoQuery(value, q.GetParamValue("radius"), q.GetParamValue("sort") == "distance")
+			if err != nil {
+				return dbQuery, err
+			}
+
+			return dbQuery.(*gorm.DB).Scopes(gq.Scope(column)), nil
+		},
+	}
+}
+
+// Scope - gorm scope that applies the query in the GeoPoint column
+func (q *GeoQuery) Scope(column string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if q.Radius > 0 {
+			db = WithinRadius(column, q.Center, q.Radius)(db)
+		}
+
+		if q.OrderByDistance {
+			db = OrderByDistance(column, q.Center)(db)
+		}
+
+		return db
+	}
+}
+
+// WithinRadius - gorm scope that filters the records with the GeoPoint column up to meters from center
+func WithinRadius(column string, center GeoPoint, meters float64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		d, err := getGeoDistance(db, column, center)
+		if err != nil {
+			db.AddError(err)
+			return db
+		}
+
+		return db.Where(clause.Expr{
+			SQL:  d.expr.SQL + " <= ?",
+			Vars: append(d.expr.Vars, d.limit(meters)),
+		})
+	}
+}
+
+// OrderByDistance - gorm scope that sorts the records by the distance between the GeoPoint column and center, nearest first
+func OrderByDistance(column string, center GeoPoint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		d, err := getGeoDistance(db, column, center)
+		if err != nil {
+			db.AddError(err)
+			return db
+		}
+
+		return db.Order(clause.OrderBy{Expression: d.expr})
+	}
+}
+
+type geoDistance struct {
+	expr clause.Expr
+	// convert one distance in meters to the expression unit
+	limit func(meters float64) float64
+}
+
+func getGeoDistance(db *gorm.DB, column string, center GeoPoint) (*geoDistance, error) {
+	col := clause.Column{Name: column}
+	if table, name, ok := strings.Cut(column, "."); ok {
+		col = clause.Column{Table: table, Name: name}
+	}
+
+	if isEmbeddedGeoPoint(db, col.Name) {
+		return getEmbeddedGeoDistance(col, center), nil
+	}
+
+	meters := func(v float64) float64 { return v }
+
+	switch db.Dialector.Name() {
+	case "mysql":
+		if center.GetSRID() == 0 {
+			// legacy points store the latitude as X, ST_Distance_Sphere expects the longitude as X
+			return &geoDistance{
+				expr: clause.Expr{
+					SQL:  "ST_Distance_Sphere(POINT(ST_Y(?), ST_X(?)), POINT(?, ?))",
+					Vars: []interface{}{col, col, center.Y, center.X},
+				},
+				limit: meters,
+			}, nil
+		}
+
+		point := mysqlGeomFromText(center)
+
+		return &geoDistance{
+			expr: clause.Expr{
+				SQL:  "ST_Distance_Sphere(?, " + point.SQL + ")",
+				Vars: append([]interface{}{col}, point.Vars...),
+			},
+			limit: meters,
+		}, nil
+	case "postgres":
+		// haversine, the point column is (lng, lat)
+		return &geoDistance{
+			expr: clause.Expr{
+				SQL: "2 * ? * ASIN(SQRT(POWER(SIN(RADIANS(?[1] - ?) / 2), 2) + COS(RADIANS(?)) * COS(RADIANS(?[1])) * POWER(SIN(RADIANS(?[0] - ?) / 2), 2)))",
+				Vars: []interface{}{
+					EarthRadius, col, center.X, center.X, col, col, center.Y,
+				},
+			},
+			limit: meters,
+		}, nil
+	default:
+		return nil, ErrGeoDistanceNotSupported
+	}
+}
+
+// Check if the model has the point as two float columns, [column]_lat and [column]_lng
+func isEmbeddedGeoPoint(db *gorm.DB, column string) bool {
+	model := db.Statement.Model
+	if model == nil {
+		model = db.Statement.Dest
+	}
+	if model == nil {
+		return false
+	}
+
+	if err := db.Statement.Parse(model); err != nil || db.Statement.Schema == nil {
+		return false
+	}
+
+	return db.Statement.Schema.LookUpField(column+"_lat") != nil && db.Statement.Schema.LookUpField(column+"_lng") != nil
+}
+
+// Equirectangular approximation with only arithmetic operations, supported by SQLite without math functions.
+// The expression is the squared distance in degrees, precise for distances of some hundreds of kilometers
+func getEmbeddedGeoDistance(col clause.Column, center GeoPoint) *geoDistance {
+	lat := clause.Column{Table: col.Table, Name: col.Name + "_lat"}
+	lng := clause.Column{Table: col.Table, Name: col.Name + "_lng"}
+	scale := math.Cos(center.X * math.Pi / 180)
+
+	return &geoDistance{
+		expr: clause.Expr{
+			SQL:  "((? - ?) * ?) * ((? - ?) * ?) + (? - ?) * (? - ?)",
+			Vars: []interface{}{lng, center.Y, scale, lng, center.Y, scale, lat, center.X, lat, center.X},
+		},
+		limit: func(meters float64) float64 {
+			degrees := meters / metersPerDegree
+			return degrees * degrees
+		},
+	}
+}
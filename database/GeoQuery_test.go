@@ -0,0 +1,191 @@
+package database_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/go-bolo/bolo/database"
+	"github.com/go-bolo/query_parser_to_db"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type geoTestPlace struct {
+	ID       uint64 `gorm:"primaryKey"`
+	Name     string
+	Location database.GeoPoint `gorm:"embedded;embeddedPrefix:location_"`
+}
+
+type geoTestColumnPlace struct {
+	ID       uint64 `gorm:"primaryKey"`
+	Location database.GeoPoint
+}
+
+func TestGeoQuery_SQLite(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, err)
+	assert.Nil(t, db.AutoMigrate(&geoTestPlace{}, &geoTestColumnPlace{}))
+
+	places := []geoTestPlace{
+		{Name: "Joinville", Location: database.NewGeoPoint(-26.3045, -48.8487)},
+		{Name: "Florianópolis", Location: database.NewGeoPoint(-27.5954, -48.548)},
+		{Name: "São José", Location: database.NewGeoPoint(-27.6136, -48.6366)},
+	}
+	assert.Nil(t, db.Create(&places).Error)
+
+	center := database.NewGeoPoint(-27.5969, -48.5495)
+
+	t.Run("should filter and sort by distance with the point in two columns", func(t *testing.T) {
+		q := database.GeoQuery{Center: center, Radius: 20000, OrderByDistance: true}
+
+		list := []geoTestPlace{}
+		err := db.Scopes(q.Scope("location")).Find(&list).Error
+		assert.Nil(t, err)
+		if assert.Len(t, list, 2) {
+			assert.Equal(t, "Florianópolis", list[0].Name)
+			assert.Equal(t, "São José", list[1].Name)
+			assert.Equal(t, places[1].Location, list[0].Location)
+		}
+	})
+
+	t.Run("should store one point column as text", func(t *testing.T) {
+		record := geoTestColumnPlace{Location: center}
+		assert.Nil(t, db.Create(&record).Error)
+
+		saved := geoTestColumnPlace{}
+		assert.Nil(t, db.First(&saved, record.ID).Error)
+		assert.Equal(t, center.X, saved.Location.X)
+		assert.Equal(t, center.Y, saved.Location.Y)
+		assert.Equal(t, 0, saved.Location.SRID)
+
+		list := []geoTestColumnPlace{}
+		err := db.Scopes(database.WithinRadius("location", center, 1000)).Find(&list).Error
+		assert.ErrorIs(t, err, database.ErrGeoDistanceNotSupported)
+	})
+}
+
+func TestGeoQuery_SQL(t *testing.T) {
+	center := database.NewGeoPoint(-27.5969, -48.5495)
+	wgs84 := database.GeoPoint{X: -27.5969, Y: -48.5495, SRID: 4326}
+	mysqlDialector := mysql.New(mysql.Config{DSN: "bolo:bolo@tcp(127.0.0.1:1)/bolo", SkipInitializeWithVersion: true})
+
+	tests := []struct {
+		name      string
+		dialector gorm.Dialector
+		center    database.GeoPoint
+		want      string
+	}{
+		{
+			name:      "mysql legacy points without srid",
+			dialector: mysqlDialector,
+			center:    center,
+			want:      "SELECT * FROM `geo_test_column_places` WHERE ST_Distance_Sphere(POINT(ST_Y(`location`), ST_X(`location`)), POINT(-48.5495, -27.5969)) <= 1000 ORDER BY ST_Distance_Sphere(POINT(ST_Y(`location`), ST_X(`location`)), POINT(-48.5495, -27.5969))",
+		},
+		{
+			name:      "mysql with srid",
+			dialector: mysqlDialector,
+			center:    wgs84,
+			want:      "SELECT * FROM `geo_test_column_places` WHERE ST_Distance_Sphere(`location`, ST_GeomFromText('POINT(-48.5495 -27.5969)', 4326, 'axis-order=long-lat')) <= 1000 ORDER BY ST_Distance_Sphere(`location`, ST_GeomFromText('POINT(-48.5495 -27.5969)', 4326, 'axis-order=long-lat'))",
+		},
+		{
+			name:      "postgres",
+			dialector: postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1"}),
+			center:    center,
+			want:      `SELECT * FROM "geo_test_column_places" WHERE 2 * 6371008.8 * ASIN(SQRT(POWER(SIN(RADIANS("location"[1] - -27.5969) / 2), 2) + COS(RADIANS(-27.5969)) * COS(RADIANS("location"[1])) * POWER(SIN(RADIANS("location"[0] - -48.5495) / 2), 2))) <= 1000`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := gorm.Open(tt.dialector, &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+			assert.Nil(t, err)
+
+			q := database.GeoQuery{Center: tt.center, Radius: 1000, OrderByDistance: tt.dialector.Name() == "mysql"}
+
+			stmt := db.Scopes(q.Scope("location")).Find(&[]geoTestColumnPlace{}).Statement
+			assert.Equal(t, tt.want, db.Dialector.Explain(stmt.SQL.String(), stmt.Vars...))
+		})
+	}
+}
+
+func TestGeoPoint_MySQLValue(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: "bolo:bolo@tcp(127.0.0.1:1)/bolo", SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name  string
+		point database.GeoPoint
+		want  string
+	}{
+		{
+			name:  "legacy layout without srid",
+			point: database.NewGeoPoint(-27.5969, -48.5495),
+			want:  "INSERT INTO `geo_test_column_places` (`location`) VALUES (ST_GeomFromText('POINT(-27.5969 -48.5495)'))",
+		},
+		{
+			name:  "long lat order with srid",
+			point: database.GeoPoint{X: -27.5969, Y: -48.5495, SRID: 4326},
+			want:  "INSERT INTO `geo_test_column_places` (`location`) VALUES (ST_GeomFromText('POINT(-48.5495 -27.5969)', 4326, 'axis-order=long-lat'))",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt := db.Create(&geoTestColumnPlace{Location: tt.point}).Statement
+			assert.Equal(t, tt.want, db.Dialector.Explain(stmt.SQL.String(), stmt.Vars...))
+		})
+	}
+}
+
+type geoTestFilterPlace struct {
+	ID       uint64 `gorm:"primaryKey"`
+	Name     string
+	Location database.GeoPoint `gorm:"embedded;embeddedPrefix:location_" filter:"param:location;type:geo"`
+}
+
+func TestGeoQuery_QueryParserFilter(t *testing.T) {
+	database.RegisterGeoFilter()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, err)
+	assert.Nil(t, db.AutoMigrate(&geoTestFilterPlace{}))
+	assert.Nil(t, db.Create(&[]geoTestFilterPlace{
+		{Name: "São José", Location: database.NewGeoPoint(-27.6136, -48.6366)},
+		{Name: "Florianópolis", Location: database.NewGeoPoint(-27.5954, -48.548)},
+		{Name: "Joinville", Location: database.NewGeoPoint(-26.3045, -48.8487)},
+	}).Error)
+
+	tests := []struct {
+		name    string
+		query   url.Values
+		want    []string
+		wantErr bool
+	}{
+		{name: "without filter", query: url.Values{}, want: []string{"São José", "Florianópolis", "Joinville"}},
+		{name: "radius and sort", query: url.Values{"location": {"-27.5969,-48.5495"}, "radius": {"20000"}, "sort": {"distance"}}, want: []string{"Florianópolis", "São José"}},
+		{name: "invalid center", query: url.Values{"location": {"-27.5969"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := query_parser_to_db.NewQuery(50)
+			q.SetLimit(10)
+			assert.Nil(t, q.ParseFromURLValues(tt.query))
+
+			list := []geoTestFilterPlace{}
+			query, err := q.SetDatabaseQueryForModel(db.Model(&geoTestFilterPlace{}), &geoTestFilterPlace{})
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Nil(t, query.(*gorm.DB).Find(&list).Error)
+
+			names := []string{}
+			for _, p := range list {
+				names = append(names, p.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
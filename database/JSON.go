@@ -0,0 +1,71 @@
+package database
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// JSON - Typed JSON column, ex: Settings database.JSON[UserSettings]
+type JSON[T any] struct {
+	Data T
+}
+
+func NewJSON[T any](data T) JSON[T] {
+	return JSON[T]{Data: data}
+}
+
+func (j JSON[T]) GormDataType() string {
+	return "json"
+}
+
+// GormDBDataType - Same types of JSONField
+func (j JSON[T]) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	return jsonDBDataType(db, field)
+}
+
+func (j JSON[T]) Value() (driver.Value, error) {
+	data, err := json.Marshal(j.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+// Scan - Decode the column value, drivers return JSON columns as []byte or string
+func (j *JSON[T]) Scan(value interface{}) error {
+	var data []byte
+
+	switch v := value.(type) {
+	case nil:
+		var zero T
+		j.Data = zero
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("expected []byte or string for JSON type, got %T", value)
+	}
+
+	if len(data) == 0 {
+		var zero T
+		j.Data = zero
+		return nil
+	}
+
+	return json.Unmarshal(data, &j.Data)
+}
+
+func (j JSON[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.Data)
+}
+
+func (j *JSON[T]) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &j.Data)
+}
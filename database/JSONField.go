@@ -3,11 +3,35 @@ package database
 import (
 	"bytes"
 	"database/sql/driver"
+	"encoding/json"
 	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 )
 
 type JSONField []byte
 
+// GormDBDataType - Use jsonb in postgres and json in other databases, the type tag has priority
+func (j JSONField) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	return jsonDBDataType(db, field)
+}
+
+func jsonDBDataType(db *gorm.DB, field *schema.Field) string {
+	if _, ok := field.TagSettings["TYPE"]; ok {
+		return ""
+	}
+
+	switch db.Dialector.Name() {
+	case "postgres":
+		return "jsonb"
+	case "sqlite":
+		return "text"
+	default:
+		return "json"
+	}
+}
+
 func (j JSONField) Value() (driver.Value, error) {
 	if j.IsNull() {
 		return nil, nil
@@ -19,11 +43,14 @@ func (j *JSONField) Scan(value interface{}) error {
 		*j = nil
 		return nil
 	}
-	s, ok := value.([]byte)
-	if !ok {
+	switch s := value.(type) {
+	case []byte:
+		*j = append((*j)[0:0], s...)
+	case string:
+		*j = append((*j)[0:0], s...)
+	default:
 		return errors.New("Invalid Scan Source")
 	}
-	*j = append((*j)[0:0], s...)
 	return nil
 }
 func (m JSONField) MarshalJSON() ([]byte, error) {
@@ -40,16 +67,14 @@ func (m *JSONField) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-// func (m *JSONField) Unmarshal(v interface{}) error {
-// 	if m == nil {
-// 		return errors.New("null point exception")
-// 	}
-//
-// 	json.Unmarshal(m, v)
-//
-// 	// *m = append((*m)[0:0], data...)
-// 	return nil
-// }
+// Unmarshal - Decode the field value in v, use JSON[T] for typed columns
+func (m JSONField) Unmarshal(v interface{}) error {
+	if m.IsNull() {
+		return nil
+	}
+
+	return json.Unmarshal(m, v)
+}
 
 func (j JSONField) IsNull() bool {
 	return len(j) == 0 || string(j) == "null"
@@ -0,0 +1,158 @@
+package database
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/go-bolo/query_parser_to_db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrInvalidJSONPath = errors.New("database: invalid JSON path")
+
+// Keys separated by dots with optional array indexes, ex: address.city or tags[0].name
+var jsonPathRegex = regexp.MustCompile(`^[A-Za-z0-9_]+(\[[0-9]+\])*(\.[A-Za-z0-9_]+(\[[0-9]+\])*)*$`)
+
+// JSONPathExpr - Get one expression with the value in the JSON column path as text,
+// JSON_EXTRACT in MySQL and SQLite and the #>> operator in Postgres
+func JSONPathExpr(db *gorm.DB, column, path string) (clause.Expr, error) {
+	if !jsonPathRegex.MatchString(path) {
+		return clause.Expr{}, ErrInvalidJSONPath
+	}
+
+	col := clause.Column{Name: column}
+	if table, name, ok := strings.Cut(column, "."); ok {
+		col = clause.Column{Table: table, Name: name}
+	}
+
+	switch db.Dialector.Name() {
+	case "postgres":
+		keys := postgresJSONPath(path)
+		vars := []interface{}{col}
+		for _, key := range keys {
+			vars = append(vars, key)
+		}
+
+		return clause.Expr{
+			SQL:  "(? #>> ARRAY[" + strings.TrimSuffix(strings.Repeat("?, ", len(keys)), ", ") + "]::text[])",
+			Vars: vars,
+		}, nil
+	case "sqlite":
+		// return booleans as true and false, like MySQL and Postgres
+		return clause.Expr{
+			SQL:  "(CASE JSON_TYPE(?, ?) WHEN 'true' THEN 'true' WHEN 'false' THEN 'false' ELSE CAST(JSON_EXTRACT(?, ?) AS TEXT) END)",
+			Vars: []interface{}{col, mysqlJSONPath(path), col, mysqlJSONPath(path)},
+		}, nil
+	default:
+		return clause.Expr{
+			SQL:  "JSON_UNQUOTE(JSON_EXTRACT(?, ?))",
+			Vars: []interface{}{col, mysqlJSONPath(path)},
+		}, nil
+	}
+}
+
+// Convert address.tags[0] to the MySQL and SQLite path $.address.tags[0], keys that start with one digit are quoted, ex: $."2fa"
+func mysqlJSONPath(path string) string {
+	keys := strings.Split(path, ".")
+	for i, key := range keys {
+		if key[0] >= '0' && key[0] <= '9' {
+			name, indexes, found := strings.Cut(key, "[")
+			keys[i] = `"` + name + `"`
+			if found {
+				keys[i] += "[" + indexes
+			}
+		}
+	}
+
+	return "$." + strings.Join(keys, ".")
+}
+
+// Convert address.tags[0] to the Postgres path keys address, tags and 0
+func postgresJSONPath(path string) []string {
+	parts := []string{}
+	for _, key := range strings.Split(path, ".") {
+		name, indexes, _ := strings.Cut(key, "[")
+		parts = append(parts, name)
+
+		if indexes != "" {
+			for _, index := range strings.Split(strings.TrimSuffix(indexes, "]"), "][") {
+				parts = append(parts, index)
+			}
+		}
+	}
+
+	return parts
+}
+
+// WhereJSONPath - gorm scope that filters one JSON column path with the query parser operators:
+// equal, not-equal, is-null, is-not-null, starts-with, not-starts-with, ends-with, not-ends-with, contains and not-contains
+func WhereJSONPath(column, path, operator, value string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		expr, err := JSONPathExpr(db, column, path)
+		if err != nil {
+			db.AddError(err)
+			return db
+		}
+
+		condition := ""
+		var v interface{} = value
+
+		switch operator {
+		case "equal":
+			condition = " = ?"
+		case "not-equal":
+			condition = " != ?"
+		case "is-null":
+			condition = " IS NULL"
+			v = nil
+		case "is-not-null":
+			condition = " IS NOT NULL"
+			v = nil
+		case "starts-with":
+			condition, v = " LIKE ?", value+"%"
+		case "not-starts-with":
+			condition, v = " NOT LIKE ?", value+"%"
+		case "ends-with":
+			condition, v = " LIKE ?", "%"+value
+		case "not-ends-with":
+			condition, v = " NOT LIKE ?", "%"+value
+		case "contains":
+			condition, v = " LIKE ?", "%"+value+"%"
+		case "not-contains":
+			condition, v = " NOT LIKE ?", "%"+value+"%"
+		default:
+			return db
+		}
+
+		vars := expr.Vars
+		if v != nil {
+			vars = append(vars, v)
+		}
+
+		return db.Where(clause.Expr{SQL: expr.SQL + condition, Vars: vars})
+	}
+}
+
+// JSONPathFilters - gorm scope that applies the query string filters of one JSON column,
+// ex: with param "settings" the query settings.theme=dark or settings.theme__starts-with=da filters the theme path
+func JSONPathFilters(q query_parser_to_db.QueryInterface, param, column string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		query, ok := q.(*query_parser_to_db.Query)
+		if !ok {
+			return db
+		}
+
+		for _, field := range query.Fields {
+			path := strings.TrimPrefix(field.ParamName, param+".")
+			if path == field.ParamName || len(field.Values) == 0 {
+				continue
+			}
+
+			db = WhereJSONPath(column, path, field.Operator, field.Values[0])(db)
+		}
+
+		return db
+	}
+}
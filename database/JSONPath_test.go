@@ -0,0 +1,103 @@
+package database_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/go-bolo/bolo/database"
+	"github.com/go-bolo/query_parser_to_db"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestJSONPathFilters(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, err)
+	assert.Nil(t, db.AutoMigrate(&jsonTestRecord{}))
+
+	records := []jsonTestRecord{
+		{Raw: database.JSONField(`{"theme":"dark","address":{"city":"Florianópolis"},"tags":["news"],"active":true}`)},
+		{Raw: database.JSONField(`{"theme":"light","address":{"city":"Joinville"},"tags":["sports"],"active":false}`)},
+		{Raw: database.JSONField(`{"theme":"darker","address":{"city":"Blumenau"},"2fa":{"enabled":"yes"}}`)},
+	}
+	assert.Nil(t, db.Create(&records).Error)
+
+	tests := []struct {
+		name    string
+		query   string
+		want    []uint64
+		wantErr error
+	}{
+		{name: "equal", query: "raw.theme=dark", want: []uint64{records[0].ID}},
+		{name: "starts with", query: "raw.theme__starts-with=dark", want: []uint64{records[0].ID, records[2].ID}},
+		{name: "nested path", query: "raw.address.city__contains=nville", want: []uint64{records[1].ID}},
+		{name: "array index", query: "raw.tags[0]=sports", want: []uint64{records[1].ID}},
+		{name: "boolean", query: "raw.active=true", want: []uint64{records[0].ID}},
+		{name: "is null", query: "raw.tags__is-null=1", want: []uint64{records[2].ID}},
+		{name: "key that starts with digit", query: "raw.2fa.enabled=yes", want: []uint64{records[2].ID}},
+		{name: "other params", query: "theme=dark", want: []uint64{records[0].ID, records[1].ID, records[2].ID}},
+		{name: "invalid path", query: "raw.theme')=1", wantErr: database.ErrInvalidJSONPath},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			assert.Nil(t, err)
+
+			q := query_parser_to_db.NewQuery(50)
+			assert.Nil(t, q.ParseFromURLValues(values))
+
+			list := []jsonTestRecord{}
+			err = db.Scopes(database.JSONPathFilters(q, "raw", "raw")).Order("id").Find(&list).Error
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.Nil(t, err)
+
+			ids := []uint64{}
+			for _, r := range list {
+				ids = append(ids, r.ID)
+			}
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+}
+
+func TestJSONPathExpr_SQL(t *testing.T) {
+	tests := []struct {
+		name      string
+		dialector gorm.Dialector
+		want      string
+	}{
+		{
+			name:      "mysql",
+			dialector: mysql.New(mysql.Config{DSN: "bolo:bolo@tcp(127.0.0.1:1)/bolo", SkipInitializeWithVersion: true}),
+			want:      "SELECT * FROM `json_test_records` WHERE JSON_UNQUOTE(JSON_EXTRACT(`raw`, '$.address.tags[0]')) = 'news'",
+		},
+		{
+			name:      "postgres",
+			dialector: postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1"}),
+			want:      `SELECT * FROM "json_test_records" WHERE ("raw" #>> ARRAY['address', 'tags', '0']::text[]) = 'news'`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := gorm.Open(tt.dialector, &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+			assert.Nil(t, err)
+
+			stmt := db.Scopes(database.WhereJSONPath("raw", "address.tags[0]", "equal", "news")).Find(&[]jsonTestRecord{}).Statement
+			assert.Equal(t, tt.want, db.Dialector.Explain(stmt.SQL.String(), stmt.Vars...))
+		})
+	}
+}
+
+func TestJSONPathExpr_MySQLDigitKeys(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: "bolo:bolo@tcp(127.0.0.1:1)/bolo", SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	assert.Nil(t, err)
+
+	stmt := db.Scopes(database.WhereJSONPath("raw", "2fa.0codes[1]", "equal", "x")).Find(&[]jsonTestRecord{}).Statement
+	assert.Equal(t, "SELECT * FROM `json_test_records` WHERE JSON_UNQUOTE(JSON_EXTRACT(`raw`, '$.\"2fa\".\"0codes\"[1]')) = 'x'", db.Dialector.Explain(stmt.SQL.String(), stmt.Vars...))
+}
@@ -0,0 +1,75 @@
+package database_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-bolo/bolo/database"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type jsonTestSettings struct {
+	Theme string   `json:"theme"`
+	Tags  []string `json:"tags"`
+}
+
+type jsonTestRecord struct {
+	ID       uint64 `gorm:"primaryKey"`
+	Settings database.JSON[jsonTestSettings]
+	Raw      database.JSONField
+}
+
+func TestJSON_Scan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     interface{}
+		want    jsonTestSettings
+		wantErr bool
+	}{
+		{name: "bytes", src: []byte(`{"theme":"dark","tags":["a"]}`), want: jsonTestSettings{Theme: "dark", Tags: []string{"a"}}},
+		{name: "string", src: `{"theme":"light"}`, want: jsonTestSettings{Theme: "light"}},
+		{name: "null", src: nil, want: jsonTestSettings{}},
+		{name: "empty", src: "", want: jsonTestSettings{}},
+		{name: "invalid json", src: `{"theme":`, wantErr: true},
+		{name: "invalid type", src: 10, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := database.JSON[jsonTestSettings]{Data: jsonTestSettings{Theme: "old"}}
+			err := j.Scan(tt.src)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, j.Data)
+		})
+	}
+}
+
+func TestJSON_Database(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, err)
+	assert.Nil(t, db.AutoMigrate(&jsonTestRecord{}))
+
+	record := jsonTestRecord{
+		Settings: database.NewJSON(jsonTestSettings{Theme: "dark", Tags: []string{"a", "b"}}),
+		Raw:      database.JSONField(`{"count":2}`),
+	}
+	assert.Nil(t, db.Create(&record).Error)
+
+	saved := jsonTestRecord{}
+	assert.Nil(t, db.First(&saved, record.ID).Error)
+	assert.Equal(t, record.Settings, saved.Settings)
+
+	raw := map[string]int{}
+	assert.Nil(t, saved.Raw.Unmarshal(&raw))
+	assert.Equal(t, 2, raw["count"])
+
+	data, err := json.Marshal(&saved)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `"Settings":{"theme":"dark","tags":["a","b"]}`)
+}
@@ -0,0 +1,307 @@
+package bolo
+
+import (
+	"database/sql"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-bolo/bolo/configuration"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// gorm statement setting that disables the read replicas in one query, see UsePrimaryDB
+const dbUsePrimaryKey = "bolo:usePrimary"
+
+// Get the configuration key of one database connection, DB_[KEY] for the default connection and DB_[NAME]_[KEY] for named connections
+func getDBConfigurationKey(name, key string) string {
+	if name == "" || name == "default" {
+		return "DB_" + key
+	}
+
+	return "DB_" + configurationKeyName(name) + "_" + key
+}
+
+func getDBConfiguration(cfg configuration.ConfigurationInterface, name, key, fallback string) string {
+	return cfg.GetF(getDBConfigurationKey(name, key), fallback)
+}
+
+// Build the driver DSN of one engine, the mysql DSN gets the charset, parseTime and loc defaults if not set.
+// The configured params are kept as is, the driver parses values like tls or time_zone without escaping
+func getDatabaseDSN(engine, dbURI string) string {
+	if engine != "mysql" {
+		return dbURI
+	}
+
+	base, query, _ := strings.Cut(dbURI, "?")
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return dbURI
+	}
+
+	missing := []string{}
+	for _, p := range [][2]string{{"charset", "utf8mb4"}, {"parseTime", "True"}, {"loc", "Local"}} {
+		if !params.Has(p[0]) {
+			missing = append(missing, p[0]+"="+p[1])
+		}
+	}
+
+	if len(missing) == 0 {
+		return dbURI
+	}
+	if query != "" {
+		missing = append([]string{query}, missing...)
+	}
+
+	return base + "?" + strings.Join(missing, "&")
+}
+
+func openDatabase(engine, dbURI string, gormCFG gorm.Option) (*gorm.DB, error) {
+	dsn := getDatabaseDSN(engine, dbURI)
+
+	switch engine {
+	case "mysql":
+		return gorm.Open(mysql.Open(dsn), gormCFG)
+	case "postgres":
+		return gorm.Open(postgres.Open(dsn), gormCFG)
+	case "sqlite":
+		return gorm.Open(sqlite.Open(dsn), gormCFG)
+	default:
+		return nil, errors.New("bolo.openDatabase invalid database engine. Options available: mysql, postgres or sqlite")
+	}
+}
+
+// Open one database and retry with exponential backoff while the server is not accepting connections, ex: containers that start before the database.
+// DB_[NAME_]CONNECT_TIMEOUT is the max seconds waiting for the database, 0 disables the retries
+func openDatabaseWithRetry(cfg configuration.ConfigurationInterface, name, engine, dbURI string, gormCFG gorm.Option) (*gorm.DB, error) {
+	timeout := time.Duration(cfg.GetInt64F(getDBConfigurationKey(name, "CONNECT_TIMEOUT"), cfg.GetInt64F("DB_CONNECT_TIMEOUT", 30))) * time.Second
+	interval := time.Duration(cfg.GetInt64F("DB_CONNECT_RETRY_INTERVAL", 500)) * time.Millisecond
+	maxInterval := time.Duration(cfg.GetInt64F("DB_CONNECT_RETRY_MAX_INTERVAL", 5000)) * time.Millisecond
+
+	deadline := time.Now().Add(timeout)
+
+	for attempt := 1; ; attempt++ {
+		db, err := openDatabase(engine, dbURI, gormCFG)
+		if err == nil {
+			return db, nil
+		}
+
+		if !isValidDBEngine(engine) || time.Now().Add(interval).After(deadline) {
+			return nil, errors.Wrapf(err, "bolo.openDatabaseWithRetry error after %d attempts", attempt)
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"name":    name,
+			"engine":  engine,
+			"attempt": attempt,
+			"retryIn": interval.String(),
+			"error":   err.Error(),
+		}).Warn("bolo.openDatabaseWithRetry database not available, retrying")
+
+		time.Sleep(interval)
+
+		interval *= 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
+
+func isValidDBEngine(engine string) bool {
+	return engine == "mysql" || engine == "postgres" || engine == "sqlite"
+}
+
+// Configure the connection pool from the DB_MAX_OPEN_CONNS, DB_MAX_IDLE_CONNS, DB_CONN_MAX_LIFETIME and DB_CONN_MAX_IDLE_TIME configurations,
+// named connections use the default connection values as fallback. Durations are in seconds
+func configureDBPool(cfg configuration.ConfigurationInterface, name string, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	maxOpen := cfg.GetIntF(getDBConfigurationKey(name, "MAX_OPEN_CONNS"), cfg.GetIntF("DB_MAX_OPEN_CONNS", 0))
+	if maxOpen > 0 {
+		sqlDB.SetMaxOpenConns(maxOpen)
+	}
+
+	maxIdle := cfg.GetIntF(getDBConfigurationKey(name, "MAX_IDLE_CONNS"), cfg.GetIntF("DB_MAX_IDLE_CONNS", 0))
+	if maxIdle > 0 {
+		sqlDB.SetMaxIdleConns(maxIdle)
+	}
+
+	maxLifetime := cfg.GetInt64F(getDBConfigurationKey(name, "CONN_MAX_LIFETIME"), cfg.GetInt64F("DB_CONN_MAX_LIFETIME", 0))
+	if maxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(time.Duration(maxLifetime) * time.Second)
+	}
+
+	maxIdleTime := cfg.GetInt64F(getDBConfigurationKey(name, "CONN_MAX_IDLE_TIME"), cfg.GetInt64F("DB_CONN_MAX_IDLE_TIME", 0))
+	if maxIdleTime > 0 {
+		sqlDB.SetConnMaxIdleTime(time.Duration(maxIdleTime) * time.Second)
+	}
+
+	return nil
+}
+
+// GetDBStats - Get the connection pool stats of all initialized connections by name
+func (r *AppStruct) GetDBStats() map[string]sql.DBStats {
+	stats := map[string]sql.DBStats{}
+
+	for name, db := range r.DBs {
+		if db == nil {
+			continue
+		}
+
+		sqlDB, err := db.DB()
+		if err != nil {
+			continue
+		}
+
+		stats[name] = sqlDB.Stats()
+	}
+
+	return stats
+}
+
+// Open the connections listed in DB_CONNECTIONS and bind the models and plugins from DB_[NAME]_MODELS and DB_[NAME]_PLUGINS
+func (r *AppStruct) initNamedDatabases() error {
+	cfg := r.GetConfiguration()
+
+	for _, name := range splitConfigurationList(cfg.GetF("DB_CONNECTIONS", "")) {
+		err := r.InitDatabase(name, cfg.GetF("DB_ENGINE", "sqlite"), false)
+		if err != nil {
+			return errors.Wrap(err, "bolo.App.initNamedDatabases error on init "+name)
+		}
+
+		for _, model := range splitConfigurationList(getDBConfiguration(cfg, name, "MODELS", "")) {
+			r.SetModelDB(model, name)
+		}
+		for _, plugin := range splitConfigurationList(getDBConfiguration(cfg, name, "PLUGINS", "")) {
+			r.SetPluginDB(plugin, name)
+		}
+	}
+
+	return nil
+}
+
+func (r *AppStruct) GetDBByName(name string) *gorm.DB {
+	if name == "" || name == "default" {
+		return r.DB
+	}
+
+	return r.DBs[name]
+}
+
+func (r *AppStruct) SetModelDB(modelName, dbName string) error {
+	r.modelDBs[modelName] = dbName
+	return nil
+}
+
+func (r *AppStruct) GetModelDB(modelName string) *gorm.DB {
+	return r.getBoundDB(r.modelDBs[modelName])
+}
+
+func (r *AppStruct) SetPluginDB(pluginName, dbName string) error {
+	r.pluginDBs[pluginName] = dbName
+	return nil
+}
+
+func (r *AppStruct) GetPluginDB(pluginName string) *gorm.DB {
+	return r.getBoundDB(r.pluginDBs[pluginName])
+}
+
+func (r *AppStruct) getBoundDB(dbName string) *gorm.DB {
+	if dbName != "" {
+		if db := r.GetDBByName(dbName); db != nil {
+			return db
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"dbName": dbName,
+		}).Warn("bolo.App.getBoundDB database connection not found, using the default connection")
+	}
+
+	return r.DB
+}
+
+// UsePrimaryDB - Run the queries of db in the primary connection, used to read data just written
+func UsePrimaryDB(db *gorm.DB) *gorm.DB {
+	return db.Set(dbUsePrimaryKey, true)
+}
+
+// dbReplicas - Send reads built by gorm to the replica pools in round robin
+type dbReplicas struct {
+	pools []gorm.ConnPool
+	next  uint64
+}
+
+func (r *dbReplicas) pick() gorm.ConnPool {
+	n := atomic.AddUint64(&r.next, 1)
+	return r.pools[(n-1)%uint64(len(r.pools))]
+}
+
+// Check if one query can run in one replica
+func (r *dbReplicas) canUseReplica(tx *gorm.DB) bool {
+	if tx.Error != nil {
+		return false
+	}
+
+	if v, ok := tx.Get(dbUsePrimaryKey); ok && v == true {
+		return false
+	}
+
+	// raw queries, also used by the gorm migrator, run in the primary
+	if tx.Statement.SQL.Len() > 0 {
+		return false
+	}
+
+	// queries inside transactions
+	if _, ok := tx.Statement.ConnPool.(gorm.TxCommitter); ok {
+		return false
+	}
+
+	// SELECT ... FOR UPDATE
+	if _, ok := tx.Statement.Clauses["FOR"]; ok {
+		return false
+	}
+
+	return true
+}
+
+// Open the replicas from the DB_[NAME]_REPLICAS uri list and route reads to them with gorm callbacks
+func registerDBReplicas(cfg configuration.ConfigurationInterface, name, engine string, uris []string, gormCFG gorm.Option, db *gorm.DB) error {
+	replicas := dbReplicas{}
+
+	for _, uri := range uris {
+		replica, err := openDatabaseWithRetry(cfg, name, engine, uri, gormCFG)
+		if err != nil {
+			return err
+		}
+
+		err = configureDBPool(cfg, name, replica)
+		if err != nil {
+			return err
+		}
+
+		replicas.pools = append(replicas.pools, replica.ConnPool)
+	}
+
+	useReplica := func(tx *gorm.DB) {
+		if replicas.canUseReplica(tx) {
+			tx.Statement.ConnPool = replicas.pick()
+		}
+	}
+
+	cb := db.Callback()
+
+	if err := cb.Query().Before("gorm:query").Register("bolo:replicas_query", useReplica); err != nil {
+		return err
+	}
+
+	return cb.Row().Before("gorm:row").Register("bolo:replicas_row", useReplica)
+}
@@ -0,0 +1,196 @@
+package bolo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-bolo/bolo/models"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Permission to list soft deleted records with the withDeleted query param
+const PermissionViewDeleted = "view_deleted"
+
+// DB - Get the default database bound to the request context. Queries are canceled when the client disconnects
+// or after the route DBTimeout, and use the request transaction in router groups with transactions enabled.
+// The authenticated user is the author of the changes, see models.Authorship
+func (r *RequestContext) DB() *gorm.DB {
+	db := r.dbTx
+	if db == nil {
+		db = r.App.GetDB()
+		if db == nil {
+			return nil
+		}
+
+		db = db.WithContext(r.getDBContext())
+	}
+
+	if r.isWithDeleted() {
+		db = db.Unscoped()
+	}
+
+	return db
+}
+
+func (r *RequestContext) getDBContext() context.Context {
+	ctx := r.dbCtx
+	if ctx == nil {
+		// contexts used in CLIs don't have one request
+		if r.echoContext == nil || r.Request() == nil {
+			return context.Background()
+		}
+
+		ctx = r.Request().Context()
+	}
+
+	if r.IsAuthenticated && r.AuthenticatedUser != nil {
+		ctx = models.ContextWithAuthor(ctx, r.AuthenticatedUser.GetID())
+	}
+
+	return ctx
+}
+
+// Check if one read request asked for soft deleted records, withDeleted=true, with the view_deleted permission
+func (r *RequestContext) isWithDeleted() bool {
+	if r.echoContext == nil || r.Request() == nil || isUnsafeMethod(r.Request().Method) {
+		return false
+	}
+
+	withDeleted, _ := strconv.ParseBool(r.QueryParam("withDeleted"))
+
+	return withDeleted && r.Can(PermissionViewDeleted)
+}
+
+// Get the query timeout of the request route, with the DB_QUERY_TIMEOUT configuration in milliseconds as fallback
+func getRequestDBTimeout(ctx *RequestContext) time.Duration {
+	route := ctx.App.GetRoute(ctx.Request().Method, ctx.Path())
+	if route != nil && route.DBTimeout > 0 {
+		return route.DBTimeout
+	}
+
+	return time.Duration(ctx.App.GetConfiguration().GetInt64F("DB_QUERY_TIMEOUT", 0)) * time.Millisecond
+}
+
+// Methods that can change data, only these requests run in one transaction
+func isUnsafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return false
+	default:
+		return true
+	}
+}
+
+// Middleware that binds the request database context and, in router groups with transactions enabled,
+// runs unsafe requests in one transaction that commits on 2xx responses and rolls back on errors.
+// Transactional responses are sent after the commit, commit errors return one 500 response
+func dbContextMiddleware(app App) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.(*RequestContext)
+
+			if timeout := getRequestDBTimeout(ctx); timeout > 0 {
+				dbCtx, cancel := context.WithTimeout(ctx.Request().Context(), timeout)
+				defer cancel()
+				ctx.dbCtx = dbCtx
+			}
+
+			if app.GetDB() == nil ||
+				!isUnsafeMethod(ctx.Request().Method) ||
+				!app.IsRouterGroupTransactionEnabled(app.GetRouterGroupNameByPath(ctx.Request().URL.Path)) {
+				return next(ctx)
+			}
+
+			tx := app.GetDB().WithContext(ctx.getDBContext()).Begin()
+			if tx.Error != nil {
+				return errors.Wrap(tx.Error, "bolo.dbContextMiddleware error on begin transaction")
+			}
+			ctx.dbTx = tx
+
+			// the response is sent only after the commit, a commit error can still return one 500 response
+			res := ctx.Response()
+			w := &transactionResponseWriter{ResponseWriter: res.Writer}
+			res.Writer = w
+
+			committed := false
+			defer func() {
+				ctx.dbTx = nil
+				if !committed {
+					tx.Rollback()
+				}
+			}()
+
+			err := next(ctx)
+			res.Writer = w.ResponseWriter
+
+			if err != nil || res.Status >= http.StatusMultipleChoices {
+				if flushErr := w.flush(); err == nil {
+					err = flushErr
+				}
+				return err
+			}
+
+			err = tx.Commit().Error
+			if err != nil {
+				logrus.WithFields(logrus.Fields{
+					"error": fmt.Sprintf("%+v\n", err),
+					"path":  ctx.Path(),
+				}).Error("bolo.dbContextMiddleware error on commit transaction")
+
+				// discard the handler response, the error handler writes the 500 response
+				res.Committed = false
+				res.Status = http.StatusOK
+				res.Size = 0
+				res.Header().Del(echo.HeaderContentLength)
+
+				return &HTTPError{
+					Code:     http.StatusInternalServerError,
+					Message:  http.StatusText(http.StatusInternalServerError),
+					Internal: errors.Wrap(err, "bolo.dbContextMiddleware error on commit transaction"),
+				}
+			}
+			committed = true
+
+			return w.flush()
+		}
+	}
+}
+
+// Response writer of the transactional requests, keeps the response in memory until the transaction is committed
+type transactionResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *transactionResponseWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+func (w *transactionResponseWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// Flush - Responses are buffered until the commit, streaming is not supported in transactions
+func (w *transactionResponseWriter) Flush() {}
+
+// Send the buffered response to the client
+func (w *transactionResponseWriter) flush() error {
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+
+	if w.body.Len() == 0 {
+		return nil
+	}
+
+	_, err := w.ResponseWriter.Write(w.body.Bytes())
+	return err
+}
@@ -0,0 +1,295 @@
+package bolo_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-bolo/bolo"
+	"github.com/go-bolo/bolo/models"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type transactionTestRecord struct {
+	ID   uint64 `gorm:"primaryKey"`
+	Name string
+}
+
+func TestRequestContext_DB(t *testing.T) {
+	// one file database, each connection of one memory database has its own data
+	os.Setenv("DB_URI", filepath.Join(t.TempDir(), "bolo.db"))
+	defer os.Unsetenv("DB_URI")
+
+	app := GetTestApp()
+	err := app.Bootstrap()
+	assert.Nil(t, err)
+	assert.Nil(t, app.GetDB().AutoMigrate(&transactionTestRecord{}))
+
+	create := func(status int) bolo.Action {
+		return func(c echo.Context) error {
+			ctx := c.(*bolo.RequestContext)
+
+			err := ctx.DB().Create(&transactionTestRecord{Name: c.QueryParam("name")}).Error
+			if err != nil {
+				return err
+			}
+
+			if status >= http.StatusBadRequest {
+				return &bolo.HTTPError{Code: status, Message: http.StatusText(status)}
+			}
+
+			return c.NoContent(status)
+		}
+	}
+
+	transactional := app.SetRouterGroup("transactional", "/transactional")
+	app.SetRouterGroupTransaction("transactional", true)
+
+	app.SetRoute(transactional, &bolo.Route{Method: http.MethodPost, Path: "/ok", Action: create(http.StatusCreated)})
+	app.SetRoute(transactional, &bolo.Route{Method: http.MethodPost, Path: "/fail", Action: create(http.StatusBadRequest)})
+	app.SetRoute(app.GetRouterGroup("main"), &bolo.Route{Method: http.MethodPost, Path: "no-transaction", Action: create(http.StatusBadRequest)})
+
+	var deadline time.Time
+	app.SetRoute(app.GetRouterGroup("main"), &bolo.Route{
+		Method:    http.MethodGet,
+		Path:      "timeout",
+		DBTimeout: 2 * time.Second,
+		Action: func(c echo.Context) error {
+			ctx := c.(*bolo.RequestContext)
+			deadline, _ = ctx.DB().Statement.Context.Deadline()
+			return c.NoContent(http.StatusOK)
+		},
+	})
+
+	serve := func(method, url string) int {
+		req := httptest.NewRequest(method, url, nil)
+		req.Header.Set(echo.HeaderAccept, "application/json")
+		rec := httptest.NewRecorder()
+		app.GetRouter().ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	exists := func(name string) bool {
+		var count int64
+		app.GetDB().Model(&transactionTestRecord{}).Where("name = ?", name).Count(&count)
+		return count > 0
+	}
+
+	tests := []struct {
+		name       string
+		url        string
+		record     string
+		wantStatus int
+		wantSaved  bool
+	}{
+		{name: "should commit 2xx responses", url: "/transactional/ok?name=committed", record: "committed", wantStatus: http.StatusCreated, wantSaved: true},
+		{name: "should rollback errors", url: "/transactional/fail?name=rolledBack", record: "rolledBack", wantStatus: http.StatusBadRequest, wantSaved: false},
+		{name: "should not use transactions in other groups", url: "/no-transaction?name=saved", record: "saved", wantStatus: http.StatusBadRequest, wantSaved: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantStatus, serve(http.MethodPost, tt.url))
+			assert.Equal(t, tt.wantSaved, exists(tt.record))
+		})
+	}
+
+	t.Run("should set the route query timeout", func(t *testing.T) {
+		start := time.Now()
+		assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/timeout"))
+		assert.WithinDuration(t, start.Add(2*time.Second), deadline, time.Second)
+	})
+
+	t.Run("should use the background context outside requests", func(t *testing.T) {
+		ctx := bolo.NewRequestContext(&bolo.RequestContextOpts{App: app})
+		assert.Equal(t, context.Background(), ctx.DB().Statement.Context)
+	})
+}
+
+type modelsTestPost struct {
+	models.Base
+	models.SoftDelete
+	models.Authorship
+	models.Versioned
+
+	Title string `json:"title"`
+}
+
+func TestRequestContext_DB_Models(t *testing.T) {
+	os.Setenv("DB_URI", filepath.Join(t.TempDir(), "bolo.db"))
+	defer os.Unsetenv("DB_URI")
+
+	app := GetTestApp()
+	err := app.Bootstrap()
+	assert.Nil(t, err)
+	assert.Nil(t, app.GetDB().AutoMigrate(&modelsTestPost{}))
+
+	deleted := modelsTestPost{Title: "deleted"}
+	assert.Nil(t, app.GetDB().Create(&deleted).Error)
+	assert.Nil(t, app.GetDB().Delete(&deleted).Error)
+
+	authenticate := func(c echo.Context, roles ...string) *bolo.RequestContext {
+		ctx := c.(*bolo.RequestContext)
+		if len(roles) > 0 {
+			ctx.SetAuthenticatedUserAndFillRoles(&UserModel{ID: "7", Roles: roles})
+		}
+		return ctx
+	}
+
+	app.SetRoute(app.GetRouterGroup("main"), &bolo.Route{
+		Method: http.MethodPost,
+		Path:   "posts",
+		Action: func(c echo.Context) error {
+			ctx := authenticate(c, "authenticated")
+
+			post := modelsTestPost{}
+			if err := c.Bind(&post); err != nil {
+				return err
+			}
+			if err := ctx.DB().Save(&post).Error; err != nil {
+				return err
+			}
+
+			return c.JSON(http.StatusOK, &post)
+		},
+	})
+	app.SetRoute(app.GetRouterGroup("main"), &bolo.Route{
+		Method: http.MethodGet,
+		Path:   "posts",
+		Action: func(c echo.Context) error {
+			ctx := authenticate(c, c.QueryParams()["role"]...)
+
+			list := []modelsTestPost{}
+			if err := ctx.DB().Find(&list).Error; err != nil {
+				return err
+			}
+
+			return c.JSON(http.StatusOK, &list)
+		},
+	})
+
+	serve := func(method, url, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		req.Header.Set(echo.HeaderAccept, "application/json")
+		req.Header.Set(echo.HeaderContentType, "application/json")
+		rec := httptest.NewRecorder()
+		app.GetRouter().ServeHTTP(rec, req)
+		return rec
+	}
+
+	created := modelsTestPost{}
+	rec := serve(http.MethodPost, "/posts", `{"title":"first"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &created))
+
+	t.Run("should set the authenticated user as author", func(t *testing.T) {
+		assert.Equal(t, "7", created.CreatedBy)
+		assert.Equal(t, "7", created.UpdatedBy)
+		assert.Equal(t, uint64(1), created.Version)
+	})
+
+	t.Run("should return 409 on updates with one old version", func(t *testing.T) {
+		body := fmt.Sprintf(`{"id":%d,"version":1,"title":"changed"}`, created.ID)
+		assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/posts", body).Code)
+
+		rec := serve(http.MethodPost, "/posts", body)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":409`)
+
+		saved := modelsTestPost{}
+		assert.Nil(t, app.GetDB().First(&saved, created.ID).Error)
+		assert.Equal(t, "changed", saved.Title)
+		assert.Equal(t, "7", saved.CreatedBy)
+		assert.Equal(t, uint64(2), saved.Version)
+	})
+
+	t.Run("should list deleted records only for admins", func(t *testing.T) {
+		tests := []struct {
+			url  string
+			want int
+		}{
+			{url: "/posts", want: 1},
+			{url: "/posts?withDeleted=true", want: 1},
+			{url: "/posts?withDeleted=true&role=authenticated", want: 1},
+			{url: "/posts?withDeleted=true&role=administrator", want: 2},
+		}
+		for _, tt := range tests {
+			list := []modelsTestPost{}
+			rec := serve(http.MethodGet, tt.url, "")
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &list))
+			assert.Len(t, list, tt.want, tt.url)
+		}
+	})
+}
+
+type transactionTestParent struct {
+	ID uint64 `gorm:"primaryKey"`
+}
+
+func TestRequestContext_DB_CommitError(t *testing.T) {
+	os.Setenv("DB_URI", "file:"+filepath.Join(t.TempDir(), "bolo.db")+"?_foreign_keys=1")
+	defer os.Unsetenv("DB_URI")
+
+	app := GetTestApp()
+	assert.Nil(t, app.Bootstrap())
+	assert.Nil(t, app.GetDB().AutoMigrate(&transactionTestParent{}))
+	// deferred foreign keys are only checked on commit
+	assert.Nil(t, app.GetDB().Exec("CREATE TABLE transaction_test_children (id INTEGER PRIMARY KEY, parent_id INTEGER REFERENCES transaction_test_parents(id) DEFERRABLE INITIALLY DEFERRED)").Error)
+
+	transactional := app.SetRouterGroup("transactional", "/transactional")
+	app.SetRouterGroupTransaction("transactional", true)
+
+	app.SetRoute(transactional, &bolo.Route{
+		Method: http.MethodPost,
+		Path:   "/children",
+		Action: func(c echo.Context) error {
+			ctx := c.(*bolo.RequestContext)
+
+			err := ctx.DB().Exec("INSERT INTO transaction_test_children (parent_id) VALUES (?)", c.QueryParam("parent")).Error
+			if err != nil {
+				return err
+			}
+
+			return c.JSON(http.StatusCreated, map[string]string{"status": "created"})
+		},
+	})
+
+	assert.Nil(t, app.GetDB().Create(&transactionTestParent{ID: 1}).Error)
+
+	tests := []struct {
+		name       string
+		parent     string
+		wantStatus int
+		wantBody   string
+		wantCount  int64
+	}{
+		{name: "should send the response after the commit", parent: "1", wantStatus: http.StatusCreated, wantBody: `{"status":"created"}`, wantCount: 1},
+		{name: "should return 500 on commit errors", parent: "2", wantStatus: http.StatusInternalServerError, wantCount: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/transactional/children?parent="+tt.parent, nil)
+			req.Header.Set(echo.HeaderAccept, "application/json")
+			rec := httptest.NewRecorder()
+			app.GetRouter().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, rec.Body.String())
+			} else {
+				assert.NotContains(t, rec.Body.String(), "created")
+			}
+
+			var count int64
+			app.GetDB().Table("transaction_test_children").Count(&count)
+			assert.Equal(t, tt.wantCount, count)
+		})
+	}
+}
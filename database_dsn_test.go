@@ -0,0 +1,46 @@
+package bolo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDatabaseDSN(t *testing.T) {
+	tests := []struct {
+		name   string
+		engine string
+		uri    string
+		want   string
+	}{
+		{
+			name:   "should add the mysql defaults",
+			engine: "mysql",
+			uri:    "user:pass@tcp(localhost:3306)/bolo",
+			want:   "user:pass@tcp(localhost:3306)/bolo?charset=utf8mb4&parseTime=True&loc=Local",
+		},
+		{
+			name:   "should keep the order and escaping of the configured params",
+			engine: "mysql",
+			uri:    "user:pass@tcp(localhost:3306)/bolo?time_zone=%27-03:00%27&tls=custom&charset=latin1",
+			want:   "user:pass@tcp(localhost:3306)/bolo?time_zone=%27-03:00%27&tls=custom&charset=latin1&parseTime=True&loc=Local",
+		},
+		{
+			name:   "should not change mysql DSNs with all params",
+			engine: "mysql",
+			uri:    "user:pass@tcp(localhost:3306)/bolo?loc=UTC&parseTime=false&charset=utf8",
+			want:   "user:pass@tcp(localhost:3306)/bolo?loc=UTC&parseTime=false&charset=utf8",
+		},
+		{
+			name:   "should not change other engines",
+			engine: "postgres",
+			uri:    "postgres://postgres@localhost:5432/bolo?sslmode=disable",
+			want:   "postgres://postgres@localhost:5432/bolo?sslmode=disable",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getDatabaseDSN(tt.engine, tt.uri))
+		})
+	}
+}
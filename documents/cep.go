@@ -0,0 +1,20 @@
+package documents
+
+const cepSize = 8
+
+// UnmaskCEP - Remove the CEP mask, ex: 01310-100 to 01310100
+func UnmaskCEP(cep string) string {
+	return unmaskReplacer.Replace(cep)
+}
+
+// MaskCEP - Format one CEP as 00000-000, values without 8 characters are returned without changes
+func MaskCEP(cep string) string {
+	return mask(UnmaskCEP(cep), "#####-###")
+}
+
+// IsValidCEP - Check the CEP size and characters, with or without mask
+func IsValidCEP(cep string) bool {
+	cep = UnmaskCEP(cep)
+
+	return len(cep) == cepSize && isDigits(cep) && !isRepeated(cep)
+}
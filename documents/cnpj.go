@@ -0,0 +1,34 @@
+package documents
+
+import "strings"
+
+const cnpjSize = 14
+
+// UnmaskCNPJ - Remove the CNPJ mask and uppercase the letters, keeping the leading zeros, ex: 12.ABC.345/01DE-35 to 12ABC34501DE35
+func UnmaskCNPJ(cnpj string) string {
+	return strings.ToUpper(unmaskReplacer.Replace(cnpj))
+}
+
+// MaskCNPJ - Format one CNPJ as 00.000.000/0000-00, values without 14 characters are returned without changes
+func MaskCNPJ(cnpj string) string {
+	return mask(UnmaskCNPJ(cnpj), "##.###.###/####-##")
+}
+
+// IsValidCNPJ - Check the CNPJ size and check digits, with or without mask. Supports the alphanumeric CNPJ,
+// with numbers and uppercase letters in the 12 first characters and numeric check digits
+func IsValidCNPJ(cnpj string) bool {
+	cnpj = UnmaskCNPJ(cnpj)
+
+	if len(cnpj) != cnpjSize || !isDigits(cnpj[12:]) || isRepeated(cnpj) {
+		return false
+	}
+
+	for _, c := range cnpj[:12] {
+		if (c < '0' || c > '9') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+
+	return cnpj[12] == checkDigit(cnpj[:12], []int{5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}) &&
+		cnpj[13] == checkDigit(cnpj[:13], []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2})
+}
@@ -0,0 +1,25 @@
+package documents
+
+const cpfSize = 11
+
+// UnmaskCPF - Remove the CPF mask, ex: 529.982.247-25 to 52998224725
+func UnmaskCPF(cpf string) string {
+	return unmaskReplacer.Replace(cpf)
+}
+
+// MaskCPF - Format one CPF as 000.000.000-00, values without 11 characters are returned without changes
+func MaskCPF(cpf string) string {
+	return mask(UnmaskCPF(cpf), "###.###.###-##")
+}
+
+// IsValidCPF - Check the CPF size and check digits, with or without mask
+func IsValidCPF(cpf string) bool {
+	cpf = UnmaskCPF(cpf)
+
+	if len(cpf) != cpfSize || !isDigits(cpf) || isRepeated(cpf) {
+		return false
+	}
+
+	return cpf[9] == checkDigit(cpf[:9], []int{10, 9, 8, 7, 6, 5, 4, 3, 2}) &&
+		cpf[10] == checkDigit(cpf[:10], []int{11, 10, 9, 8, 7, 6, 5, 4, 3, 2})
+}
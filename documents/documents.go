@@ -0,0 +1,57 @@
+// Package documents validates and formats brazilian documents: CPF, CNPJ and CEP
+package documents
+
+import (
+	"strings"
+)
+
+// Remove the mask characters, keeps other characters to be rejected in the validation
+var unmaskReplacer = strings.NewReplacer(".", "", "-", "", "/", "", " ", "")
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Documents with all characters equal pass the check digits but are invalid, ex: 000.000.000-00
+func isRepeated(s string) bool {
+	return strings.Count(s, s[:1]) == len(s)
+}
+
+// Calculate one modulo 11 check digit, the value of each character is the ASCII code minus 48
+func checkDigit(s string, weights []int) byte {
+	sum := 0
+	for i := range s {
+		sum += int(s[i]-'0') * weights[i]
+	}
+
+	if r := sum % 11; r >= 2 {
+		return byte('0' + 11 - r)
+	}
+
+	return '0'
+}
+
+// Apply one mask with # as the document characters, returns the value without changes if the size does not match
+func mask(value, pattern string) string {
+	if len(value) != strings.Count(pattern, "#") {
+		return value
+	}
+
+	var b strings.Builder
+	i := 0
+	for _, c := range pattern {
+		if c == '#' {
+			b.WriteByte(value[i])
+			i++
+			continue
+		}
+		b.WriteRune(c)
+	}
+
+	return b.String()
+}
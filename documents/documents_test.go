@@ -0,0 +1,102 @@
+package documents_test
+
+import (
+	"testing"
+
+	"github.com/go-bolo/bolo/documents"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCPF(t *testing.T) {
+	tests := []struct {
+		value  string
+		valid  bool
+		masked string
+	}{
+		{value: "529.982.247-25", valid: true, masked: "529.982.247-25"},
+		{value: "52998224725", valid: true, masked: "529.982.247-25"},
+		{value: "01234567890", valid: true, masked: "012.345.678-90"},
+		{value: "529.982.247-26", valid: false, masked: "529.982.247-26"},
+		{value: "111.111.111-11", valid: false, masked: "111.111.111-11"},
+		{value: "5299822472A", valid: false, masked: "529.982.247-2A"},
+		{value: "5299822472", valid: false, masked: "5299822472"},
+		{value: "", valid: false, masked: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.valid, documents.IsValidCPF(tt.value))
+			assert.Equal(t, tt.masked, documents.MaskCPF(tt.value))
+		})
+	}
+}
+
+func TestCNPJ(t *testing.T) {
+	tests := []struct {
+		value    string
+		valid    bool
+		masked   string
+		unmasked string
+	}{
+		{value: "11.222.333/0001-81", valid: true, masked: "11.222.333/0001-81", unmasked: "11222333000181"},
+		{value: "00623904000173", valid: true, masked: "00.623.904/0001-73", unmasked: "00623904000173"},
+		{value: "12.ABC.345/01DE-35", valid: true, masked: "12.ABC.345/01DE-35", unmasked: "12ABC34501DE35"},
+		{value: "12abc34501de35", valid: true, masked: "12.ABC.345/01DE-35", unmasked: "12ABC34501DE35"},
+		{value: "12.ABC.345/01DE-36", valid: false, masked: "12.ABC.345/01DE-36", unmasked: "12ABC34501DE36"},
+		{value: "12.ABC.345/01DE-3A", valid: false, masked: "12.ABC.345/01DE-3A", unmasked: "12ABC34501DE3A"},
+		{value: "11.222.333/0001-80", valid: false, masked: "11.222.333/0001-80", unmasked: "11222333000180"},
+		{value: "00.000.000/0000-00", valid: false, masked: "00.000.000/0000-00", unmasked: "00000000000000"},
+		{value: "12.AB_.345/01DE-35", valid: false, masked: "12.AB_.345/01DE-35", unmasked: "12AB_34501DE35"},
+		{value: "1122233300018", valid: false, masked: "1122233300018", unmasked: "1122233300018"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.valid, documents.IsValidCNPJ(tt.value))
+			assert.Equal(t, tt.masked, documents.MaskCNPJ(tt.value))
+			assert.Equal(t, tt.unmasked, documents.UnmaskCNPJ(tt.value))
+		})
+	}
+}
+
+func TestCEP(t *testing.T) {
+	tests := []struct {
+		value  string
+		valid  bool
+		masked string
+	}{
+		{value: "01310-100", valid: true, masked: "01310-100"},
+		{value: "01310100", valid: true, masked: "01310-100"},
+		{value: "00000-000", valid: false, masked: "00000-000"},
+		{value: "0131010", valid: false, masked: "0131010"},
+		{value: "0131A100", valid: false, masked: "0131A-100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.valid, documents.IsValidCEP(tt.value))
+			assert.Equal(t, tt.masked, documents.MaskCEP(tt.value))
+		})
+	}
+}
+
+func TestRegisterValidations(t *testing.T) {
+	type company struct {
+		CPF  string `validate:"omitempty,cpf"`
+		CNPJ string `validate:"cnpj"`
+		CEP  string `validate:"cep"`
+	}
+
+	v := validator.New()
+	assert.Nil(t, documents.RegisterValidations(v))
+
+	assert.Nil(t, v.Struct(&company{CNPJ: "12.ABC.345/01DE-35", CEP: "01310-100"}))
+	assert.Nil(t, v.Struct(&company{CPF: "52998224725", CNPJ: "11222333000181", CEP: "01310100"}))
+
+	err := v.Struct(&company{CPF: "111.111.111-11", CNPJ: "11222333000180", CEP: ""})
+	if assert.IsType(t, validator.ValidationErrors{}, err) {
+		tags := []string{}
+		for _, fe := range err.(validator.ValidationErrors) {
+			tags = append(tags, fe.Tag())
+		}
+		assert.Equal(t, []string{"cpf", "cnpj", "cep"}, tags)
+	}
+}
@@ -0,0 +1,30 @@
+package documents
+
+import (
+	"reflect"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// RegisterValidations - Register the cpf, cnpj and cep validator tags, ex: `validate:"omitempty,cpf"`
+func RegisterValidations(v *validator.Validate) error {
+	validations := map[string]func(string) bool{
+		"cpf":  IsValidCPF,
+		"cnpj": IsValidCNPJ,
+		"cep":  IsValidCEP,
+	}
+
+	for tag, isValid := range validations {
+		isValid := isValid
+
+		err := v.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+			field := fl.Field()
+			return field.Kind() == reflect.String && isValid(field.String())
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
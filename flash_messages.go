@@ -0,0 +1,116 @@
+package bolo
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// Session key used to store flash messages between requests
+const SessionFlashMessagesKey = "flashMessages"
+
+func getFlashMessagesStore(app App) string {
+	return app.GetConfiguration().GetF("FLASH_MESSAGES_STORE", "session")
+}
+
+func getFlashMessagesCookieName(app App) string {
+	return app.GetConfiguration().GetF("FLASH_MESSAGES_COOKIE_NAME", "bolo_flash")
+}
+
+// Persist the response messages to be restored in the next request, used on redirects
+func (r *RequestContext) persistFlashMessages() error {
+	if len(r.responseMessages) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(r.responseMessages)
+	if err != nil {
+		return err
+	}
+
+	if getFlashMessagesStore(r.App) == "cookie" {
+		r.SetCookie(&http.Cookie{
+			Name:     getFlashMessagesCookieName(r.App),
+			Value:    SignValue(r.App.GetSecret(), base64.RawURLEncoding.EncodeToString(data)),
+			Path:     "/",
+			HttpOnly: true,
+			Secure:   r.Protocol == "https",
+			SameSite: http.SameSiteLaxMode,
+		})
+
+		return nil
+	}
+
+	r.Session.Set(SessionFlashMessagesKey, string(data))
+
+	return nil
+}
+
+// Restore the flash messages saved in the previous request and remove them from the store
+func (r *RequestContext) restoreFlashMessages() error {
+	var data []byte
+
+	if getFlashMessagesStore(r.App) == "cookie" {
+		cookie, err := r.Cookie(getFlashMessagesCookieName(r.App))
+		if err != nil || cookie.Value == "" {
+			return nil
+		}
+
+		r.SetCookie(&http.Cookie{
+			Name:   getFlashMessagesCookieName(r.App),
+			Path:   "/",
+			MaxAge: -1,
+		})
+
+		value, err := VerifySignedValue(r.App.GetSecret(), cookie.Value)
+		if err != nil {
+			return err
+		}
+
+		data, err = base64.RawURLEncoding.DecodeString(value)
+		if err != nil {
+			return err
+		}
+	} else {
+		value := r.Session.GetString(SessionFlashMessagesKey)
+		if value == "" {
+			return nil
+		}
+
+		r.Session.Delete(SessionFlashMessagesKey)
+		data = []byte(value)
+	}
+
+	messages := []*ResponseMessage{}
+	err := json.Unmarshal(data, &messages)
+	if err != nil {
+		return err
+	}
+
+	r.responseMessages = append(messages, r.responseMessages...)
+
+	return nil
+}
+
+// Middleware that restores flash messages from the previous request in the response messages
+func flashMessagesMiddleware(app App) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.(*RequestContext)
+
+			err := ctx.restoreFlashMessages()
+			if err != nil {
+				logrus.WithFields(logrus.Fields{
+					"error": fmt.Sprintf("%+v\n", err),
+					"path":  c.Path(),
+				}).Warn("bolo.flashMessagesMiddleware error on restore flash messages")
+			}
+
+			return next(ctx)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package bolo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-bolo/bolo/format"
+)
+
+// TimezoneUser - Optional UserInterface method with the user timezone, ex: America/Sao_Paulo
+type TimezoneUser interface {
+	GetTimezone() string
+}
+
+// GetTimezone - Get the timezone of the authenticated user or the SITE_TIMEZONE, UTC if both are empty
+func (r *RequestContext) GetTimezone() (*time.Location, error) {
+	if tu, ok := r.AuthenticatedUser.(TimezoneUser); ok && tu.GetTimezone() != "" {
+		return format.LoadLocation(tu.GetTimezone())
+	}
+
+	return format.LoadLocation(r.App.GetConfiguration().GetF("SITE_TIMEZONE", ""))
+}
+
+// GetFormatter - Get the number, currency and date formatter of the request locale and timezone.
+// FORMAT_DECIMAL_SEPARATOR and FORMAT_THOUSANDS_SEPARATOR override the locale separators
+func (r *RequestContext) GetFormatter() (*format.Formatter, error) {
+	if r.formatter != nil {
+		return r.formatter, nil
+	}
+
+	loc, err := r.GetTimezone()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := r.App.GetConfiguration()
+
+	f := format.New(r.GetLocale())
+	f.Location = loc
+	f.Clock = r.App.GetClock()
+	f.Catalog = r.App.GetI18n()
+	if separator := cfg.GetF("FORMAT_DECIMAL_SEPARATOR", ""); separator != "" {
+		f.Symbols.Decimal = separator
+	}
+	if separator := cfg.GetF("FORMAT_THOUSANDS_SEPARATOR", ""); separator != "" {
+		f.Symbols.Thousands = separator
+	}
+
+	r.formatter = f
+
+	return f, nil
+}
+
+func toTime(v interface{}) (time.Time, bool, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, !t.IsZero(), nil
+	case *time.Time:
+		if t == nil {
+			return time.Time{}, false, nil
+		}
+		return *t, !t.IsZero(), nil
+	default:
+		return time.Time{}, false, fmt.Errorf("bolo: invalid time %v of type %T", v, v)
+	}
+}
+
+// Template function that formats one number in the request locale, ex: {{ formatDecimal .Ctx .Record.Price 2 }}
+func formatDecimal(ctx *RequestContext, value interface{}, places int) (string, error) {
+	f, err := ctx.GetFormatter()
+	if err != nil {
+		return "", err
+	}
+
+	n, err := format.ToDecimal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return f.Decimal(n, int32(places)), nil
+}
+
+// Template function that formats one amount in the request locale, ex: {{ formatCurrency .Ctx .Record.Price "BRL" }}
+func formatCurrency(ctx *RequestContext, value interface{}, currency string) (string, error) {
+	f, err := ctx.GetFormatter()
+	if err != nil {
+		return "", err
+	}
+
+	n, err := format.ToDecimal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return f.Currency(n, currency), nil
+}
+
+// Template function that formats one date in the request timezone, ex: {{ formatDate .Ctx .Record.CreatedAt "02/01/2006" }}.
+// Empty dates are formatted as empty strings
+func formatDate(ctx *RequestContext, value interface{}, layout string) (string, error) {
+	t, ok, err := toTime(value)
+	if err != nil || !ok {
+		return "", err
+	}
+
+	f, err := ctx.GetFormatter()
+	if err != nil {
+		return "", err
+	}
+
+	return f.Date(t, layout), nil
+}
+
+// Template function that formats one date relative to the app clock, ex: {{ relativeTime .Ctx .Record.CreatedAt }}
+func relativeTime(ctx *RequestContext, value interface{}) (string, error) {
+	t, ok, err := toTime(value)
+	if err != nil || !ok {
+		return "", err
+	}
+
+	f, err := ctx.GetFormatter()
+	if err != nil {
+		return "", err
+	}
+
+	return f.RelativeTime(t), nil
+}
+
+// Template function with the app clock time in the request timezone, registered as now, ex: {{ (now .Ctx).Year }}
+func currentTime(ctx *RequestContext) (time.Time, error) {
+	f, err := ctx.GetFormatter()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return f.Now(), nil
+}
@@ -0,0 +1,58 @@
+package format
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// LoadLocation - Load one IANA timezone, ex: America/Sao_Paulo. Empty names are UTC
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("format: invalid timezone %q: %w", name, err)
+	}
+
+	return loc, nil
+}
+
+// Relative time units, used in the bolo.time.[unit]Ago and bolo.time.[unit]FromNow messages
+const (
+	UnitMinutes = "minutes"
+	UnitHours   = "hours"
+	UnitDays    = "days"
+	UnitMonths  = "months"
+	UnitYears   = "years"
+)
+
+// RelativeTimeUnit - Get the rounded unit and value of one duration, the unit is empty for durations below 45 seconds
+func RelativeTimeUnit(d time.Duration) (string, int) {
+	if d < 0 {
+		d = -d
+	}
+
+	round := func(v float64) int {
+		return int(math.Max(1, math.Round(v)))
+	}
+
+	days := d.Hours() / 24
+
+	switch {
+	case d < 45*time.Second:
+		return "", 0
+	case d < 45*time.Minute:
+		return UnitMinutes, round(d.Minutes())
+	case d < 22*time.Hour:
+		return UnitHours, round(d.Hours())
+	case days < 26:
+		return UnitDays, round(days)
+	case days < 320:
+		return UnitMonths, round(days / 30)
+	default:
+		return UnitYears, round(days / 365)
+	}
+}
@@ -0,0 +1,119 @@
+package format
+
+import (
+	"embed"
+	"io/fs"
+	"strings"
+	"time"
+
+	"github.com/go-bolo/bolo/i18n"
+	"github.com/go-bolo/clock"
+	"github.com/shopspring/decimal"
+)
+
+//go:embed translations
+var translations embed.FS
+
+// Messages - Relative time messages, [locale].json files to load in one i18n catalog
+var Messages, _ = fs.Sub(translations, "translations")
+
+// messages used by the formatters without catalog
+var defaultCatalog = newDefaultCatalog()
+
+func newDefaultCatalog() *i18n.Catalog {
+	c := i18n.New("en")
+	if err := c.LoadFS(Messages, "."); err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Formatter - Number, currency and date formatter of one locale and timezone
+type Formatter struct {
+	Locale   string
+	Symbols  Symbols
+	Location *time.Location
+	Clock    clock.Clock
+	// Catalog with the relative time messages, see Messages
+	Catalog *i18n.Catalog
+}
+
+// New - Create one formatter with the locale symbols, in UTC and with the system clock
+func New(locale string) *Formatter {
+	locale = i18n.NormalizeLocale(locale)
+
+	return &Formatter{
+		Locale:   locale,
+		Symbols:  GetSymbols(locale),
+		Location: time.UTC,
+		Clock:    clock.New(),
+	}
+}
+
+// Decimal - Format one number with the locale separators, rounded to the places or with all decimals if places < 0
+func (f *Formatter) Decimal(n decimal.Decimal, places int32) string {
+	return FormatNumber(n, places, f.Symbols.Decimal, f.Symbols.Thousands)
+}
+
+// Currency - Format one amount of the ISO 4217 currency, ex: R$ 1.234,50 for BRL in pt-BR
+func (f *Formatter) Currency(n decimal.Decimal, currency string) string {
+	currency = strings.ToUpper(currency)
+
+	places, ok := currencyPlaces[currency]
+	if !ok {
+		places = 2
+	}
+
+	symbol := currencySymbols[currency]
+	if symbol == "" {
+		symbol = currency
+	}
+
+	pattern := f.Symbols.CurrencyPattern
+	if pattern == "" {
+		pattern = "¤#"
+	}
+
+	sign := ""
+	if n.IsNegative() {
+		sign, n = "-", n.Neg()
+	}
+
+	text := strings.NewReplacer("¤", symbol, "#", f.Decimal(n, places)).Replace(pattern)
+
+	return sign + text
+}
+
+// Now - Get the clock time in the formatter timezone
+func (f *Formatter) Now() time.Time {
+	return f.Clock.Now().In(f.Location)
+}
+
+// Date - Format the time in the formatter timezone with one Go layout, ex: 02/01/2006 15:04
+func (f *Formatter) Date(t time.Time, layout string) string {
+	return t.In(f.Location).Format(layout)
+}
+
+// RelativeTime - Format the time relative to the clock time, ex: 3 hours ago or in 2 days
+func (f *Formatter) RelativeTime(t time.Time) string {
+	d := f.Clock.Now().Sub(t)
+
+	unit, n := RelativeTimeUnit(d)
+	if unit == "" {
+		return f.t("bolo.time.justNow")
+	}
+
+	if d < 0 {
+		return f.t("bolo.time."+unit+"FromNow", n)
+	}
+
+	return f.t("bolo.time."+unit+"Ago", n)
+}
+
+func (f *Formatter) t(key string, args ...interface{}) string {
+	if f.Catalog == nil {
+		return defaultCatalog.T(f.Locale, key, args...)
+	}
+
+	return f.Catalog.T(f.Locale, key, args...)
+}
@@ -0,0 +1,138 @@
+package format_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-bolo/bolo/format"
+	"github.com/go-bolo/clock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatter_Decimal(t *testing.T) {
+	tests := []struct {
+		locale string
+		value  string
+		places int32
+		want   string
+	}{
+		{locale: "en", value: "1234567.891", places: 2, want: "1,234,567.89"},
+		{locale: "en", value: "-1234.5", places: -1, want: "-1,234.5"},
+		{locale: "pt-BR", value: "1234567.891", places: 2, want: "1.234.567,89"},
+		{locale: "pt_br", value: "0.5", places: 0, want: "1"},
+		{locale: "fr", value: "1234.5", places: 1, want: "1 234,5"},
+		{locale: "es-AR", value: "999", places: 2, want: "999,00"},
+		{locale: "ja", value: "1234.5", places: 1, want: "1,234.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale+" "+tt.value, func(t *testing.T) {
+			f := format.New(tt.locale)
+			assert.Equal(t, tt.want, f.Decimal(decimal.RequireFromString(tt.value), tt.places))
+		})
+	}
+}
+
+func TestFormatter_Currency(t *testing.T) {
+	tests := []struct {
+		locale   string
+		value    string
+		currency string
+		want     string
+	}{
+		{locale: "en", value: "1234.5", currency: "USD", want: "$1,234.50"},
+		{locale: "en", value: "-10", currency: "usd", want: "-$10.00"},
+		{locale: "pt-BR", value: "1234.5", currency: "BRL", want: "R$ 1.234,50"},
+		{locale: "de", value: "1234.5", currency: "EUR", want: "1.234,50 €"},
+		{locale: "en", value: "1234.5", currency: "JPY", want: "¥1,235"},
+		{locale: "en", value: "10", currency: "CHF", want: "CHF10.00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale+" "+tt.currency, func(t *testing.T) {
+			f := format.New(tt.locale)
+			assert.Equal(t, tt.want, f.Currency(decimal.RequireFromString(tt.value), tt.currency))
+		})
+	}
+}
+
+func TestFormatter_RelativeTime(t *testing.T) {
+	c := clock.NewMock()
+	now := time.Date(2023, 7, 16, 12, 0, 0, 0, time.UTC)
+	c.Set(now)
+
+	tests := []struct {
+		locale string
+		d      time.Duration
+		want   string
+	}{
+		{locale: "en", d: -10 * time.Second, want: "just now"},
+		{locale: "en", d: -time.Minute, want: "1 minute ago"},
+		{locale: "en", d: -3 * time.Hour, want: "3 hours ago"},
+		{locale: "en", d: 2 * 24 * time.Hour, want: "in 2 days"},
+		{locale: "en", d: -60 * 24 * time.Hour, want: "2 months ago"},
+		{locale: "en", d: -400 * 24 * time.Hour, want: "1 year ago"},
+		{locale: "pt-BR", d: -5 * time.Minute, want: "há 5 minutos"},
+		{locale: "pt-BR", d: 24 * time.Hour, want: "em 1 dia"},
+		{locale: "es", d: -2 * time.Hour, want: "hace 2 horas"},
+		{locale: "de", d: -2 * time.Hour, want: "2 hours ago"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale+" "+tt.want, func(t *testing.T) {
+			f := format.New(tt.locale)
+			f.Clock = c
+
+			assert.Equal(t, tt.want, f.RelativeTime(now.Add(tt.d)))
+		})
+	}
+}
+
+func TestFormatter_Date(t *testing.T) {
+	c := clock.NewMock()
+	c.Set(time.Date(2023, 7, 16, 1, 30, 0, 0, time.UTC))
+
+	loc, err := format.LoadLocation("America/Sao_Paulo")
+	assert.Nil(t, err)
+
+	f := format.New("pt-BR")
+	f.Clock = c
+	f.Location = loc
+
+	assert.Equal(t, "15/07/2023 22:30", f.Date(c.Now(), "02/01/2006 15:04"))
+	assert.Equal(t, "2023-07-15", f.Now().Format("2006-01-02"))
+
+	_, err = format.LoadLocation("Invalid/Zone")
+	assert.NotNil(t, err)
+
+	utc, err := format.LoadLocation("")
+	assert.Nil(t, err)
+	assert.Equal(t, time.UTC, utc)
+}
+
+func TestToDecimal(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    string
+		wantErr bool
+	}{
+		{name: "decimal", value: decimal.NewFromInt(5), want: "5"},
+		{name: "nil decimal pointer", value: (*decimal.Decimal)(nil), want: "0"},
+		{name: "int", value: 10, want: "10"},
+		{name: "float", value: 1.25, want: "1.25"},
+		{name: "string", value: "3.5", want: "3.5"},
+		{name: "invalid string", value: "abc", wantErr: true},
+		{name: "invalid type", value: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := format.ToDecimal(tt.value)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, n.String())
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// Symbols - Number and currency symbols of one locale
+type Symbols struct {
+	Decimal   string
+	Thousands string
+	// Position of the currency symbol (¤) and the number (#), ex: "¤ #" or "# ¤"
+	CurrencyPattern string
+}
+
+var (
+	symbolsMu sync.RWMutex
+	// symbols by locale or language, locales without symbols use the language or the english symbols
+	symbols = map[string]Symbols{
+		"en":    {Decimal: ".", Thousands: ",", CurrencyPattern: "¤#"},
+		"pt":    {Decimal: ",", Thousands: ".", CurrencyPattern: "¤ #"},
+		"pt-PT": {Decimal: ",", Thousands: " ", CurrencyPattern: "# ¤"},
+		"es":    {Decimal: ",", Thousands: ".", CurrencyPattern: "# ¤"},
+		"es-MX": {Decimal: ".", Thousands: ",", CurrencyPattern: "¤#"},
+		"de":    {Decimal: ",", Thousands: ".", CurrencyPattern: "# ¤"},
+		"fr":    {Decimal: ",", Thousands: " ", CurrencyPattern: "# ¤"},
+		"it":    {Decimal: ",", Thousands: ".", CurrencyPattern: "# ¤"},
+	}
+
+	currencySymbols = map[string]string{
+		"BRL": "R$",
+		"USD": "$",
+		"EUR": "€",
+		"GBP": "£",
+		"JPY": "¥",
+		"ARS": "$",
+		"MXN": "$",
+	}
+
+	// currencies without 2 decimal places
+	currencyPlaces = map[string]int32{
+		"JPY": 0,
+		"KRW": 0,
+		"CLP": 0,
+	}
+)
+
+// GetSymbols - Get the symbols of the locale, the locale language or the english symbols
+func GetSymbols(locale string) Symbols {
+	symbolsMu.RLock()
+	defer symbolsMu.RUnlock()
+
+	if s, ok := symbols[locale]; ok {
+		return s
+	}
+
+	base, _, _ := strings.Cut(locale, "-")
+	if s, ok := symbols[base]; ok {
+		return s
+	}
+
+	return symbols["en"]
+}
+
+// SetSymbols - Add or replace the symbols of one locale or language
+func SetSymbols(locale string, s Symbols) {
+	symbolsMu.Lock()
+	defer symbolsMu.Unlock()
+
+	symbols[locale] = s
+}
+
+// FormatNumber - Format one decimal with the separators, rounded to the places or with all decimals if places < 0
+func FormatNumber(n decimal.Decimal, places int32, decimalSeparator, thousandsSeparator string) string {
+	var s string
+	if places < 0 {
+		s = n.String()
+	} else {
+		s = n.StringFixed(places)
+	}
+
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+
+	integer, fraction, _ := strings.Cut(s, ".")
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(thousandsSeparator)
+		}
+		b.WriteRune(digit)
+	}
+
+	if fraction != "" {
+		b.WriteString(decimalSeparator)
+		b.WriteString(fraction)
+	}
+
+	return b.String()
+}
+
+// ToDecimal - Convert one decimal, number or numeric string to decimal
+func ToDecimal(v interface{}) (decimal.Decimal, error) {
+	switch n := v.(type) {
+	case decimal.Decimal:
+		return n, nil
+	case *decimal.Decimal:
+		if n == nil {
+			return decimal.Zero, nil
+		}
+		return *n, nil
+	case decimal.NullDecimal:
+		return n.Decimal, nil
+	case int:
+		return decimal.NewFromInt(int64(n)), nil
+	case int32:
+		return decimal.NewFromInt32(n), nil
+	case int64:
+		return decimal.NewFromInt(n), nil
+	case uint:
+		return decimal.NewFromInt(int64(n)), nil
+	case uint64:
+		return decimal.NewFromInt(int64(n)), nil
+	case float32:
+		return decimal.NewFromFloat32(n), nil
+	case float64:
+		return decimal.NewFromFloat(n), nil
+	case string:
+		return decimal.NewFromString(n)
+	default:
+		return decimal.Zero, fmt.Errorf("format: invalid number %v of type %T", v, v)
+	}
+}
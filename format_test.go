@@ -0,0 +1,67 @@
+package bolo_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-bolo/bolo"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type timezoneTestUser struct {
+	UserModel
+	Timezone string
+}
+
+func (r *timezoneTestUser) GetTimezone() string { return r.Timezone }
+
+func TestRequestContext_GetFormatter(t *testing.T) {
+	app := GetTestApp()
+	assert.Nil(t, app.Bootstrap())
+
+	newCtx := func(acceptLanguage string, user bolo.UserInterface) *bolo.RequestContext {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Language", acceptLanguage)
+		c := app.GetRouter().NewContext(req, httptest.NewRecorder())
+		ctx := bolo.NewRequestContext(&bolo.RequestContextOpts{App: app, EchoContext: c})
+		ctx.AuthenticatedUser = user
+		return ctx
+	}
+
+	t.Run("Should format with the request locale and the app clock", func(t *testing.T) {
+		f, err := newCtx("pt-BR", nil).GetFormatter()
+		assert.Nil(t, err)
+
+		assert.Equal(t, "R$ 1.234,50", f.Currency(decimal.RequireFromString("1234.5"), "BRL"))
+		assert.Equal(t, app.GetClock().Now(), f.Now())
+		assert.Equal(t, "há 2 dias", f.RelativeTime(app.GetClock().Now().AddDate(0, 0, -2)))
+	})
+
+	t.Run("Should use the user timezone before the site timezone", func(t *testing.T) {
+		t.Setenv("SITE_TIMEZONE", "Europe/Lisbon")
+
+		f, err := newCtx("en", &timezoneTestUser{Timezone: "America/Sao_Paulo"}).GetFormatter()
+		assert.Nil(t, err)
+		assert.Equal(t, "2023-07-15 21:00", f.Date(app.GetClock().Now(), "2006-01-02 15:04"))
+
+		f, err = newCtx("en", &UserModel{}).GetFormatter()
+		assert.Nil(t, err)
+		assert.Equal(t, "2023-07-16 01:00", f.Date(app.GetClock().Now(), "2006-01-02 15:04"))
+	})
+
+	t.Run("Should return error with invalid timezone", func(t *testing.T) {
+		_, err := newCtx("en", &timezoneTestUser{Timezone: "Invalid/Zone"}).GetFormatter()
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Should override the locale separators", func(t *testing.T) {
+		t.Setenv("FORMAT_DECIMAL_SEPARATOR", ",")
+		t.Setenv("FORMAT_THOUSANDS_SEPARATOR", " ")
+
+		f, err := newCtx("en", nil).GetFormatter()
+		assert.Nil(t, err)
+		assert.Equal(t, "1 234,50", f.Decimal(decimal.RequireFromString("1234.5"), 2))
+	})
+}
@@ -0,0 +1,233 @@
+package bolo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	HealthStatusOK    = "ok"
+	HealthStatusError = "error"
+)
+
+// HealthCheck - Dependency checked by the /health/ready endpoint
+type HealthCheck struct {
+	Name string
+	// Max check duration, default: HEALTH_CHECK_TIMEOUT seconds
+	Timeout time.Duration
+	Check   func(ctx context.Context, app App) error
+	// Optional extra information added to the check result, ex: connection pool stats
+	Details func(app App) map[string]interface{}
+}
+
+type HealthCheckResult struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+type HealthResponse struct {
+	Status string               `json:"status"`
+	Checks []*HealthCheckResult `json:"checks,omitempty"`
+}
+
+// Check that one database connection is alive
+func newDBHealthCheck(name string, db *gorm.DB) *HealthCheck {
+	return &HealthCheck{
+		Name: "db:" + name,
+		Check: func(ctx context.Context, app App) error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+
+			return sqlDB.PingContext(ctx)
+		},
+		Details: func(app App) map[string]interface{} {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return nil
+			}
+
+			stats := sqlDB.Stats()
+
+			return map[string]interface{}{
+				"openConnections":    stats.OpenConnections,
+				"inUse":              stats.InUse,
+				"idle":               stats.Idle,
+				"maxOpenConnections": stats.MaxOpenConnections,
+				"waitCount":          stats.WaitCount,
+				"waitDurationMs":     stats.WaitDuration.Milliseconds(),
+			}
+		},
+	}
+}
+
+// Check that all plugin migrations ran without errors
+func newMigrationsHealthCheck() *HealthCheck {
+	return &HealthCheck{
+		Name: "migrations",
+		Check: func(ctx context.Context, app App) error {
+			m := NewMigrationEngine(&NewMigrationEngineOpts{App: app})
+			list, err := m.GetStatus()
+			if err != nil {
+				return err
+			}
+
+			problems := []string{}
+			for _, status := range list {
+				if status.LastError != "" {
+					problems = append(problems, status.PluginName+" failed")
+				} else if status.Pending > 0 {
+					problems = append(problems, fmt.Sprintf("%s has %d pending", status.PluginName, status.Pending))
+				}
+			}
+
+			if len(problems) > 0 {
+				return fmt.Errorf("migrations not up to date: %s", strings.Join(problems, ", "))
+			}
+
+			return nil
+		},
+	}
+}
+
+// Get the database and migration checks and the checks added with App.AddHealthCheck
+func getReadinessChecks(app App) []*HealthCheck {
+	checks := []*HealthCheck{}
+
+	if app.GetDB() != nil {
+		checks = append(checks, newDBHealthCheck("default", app.GetDB()))
+	}
+
+	if dbs := app.GetDBs(); len(dbs) > 0 {
+		names := make([]string, 0, len(dbs))
+		for name := range dbs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if dbs[name] != nil && dbs[name] != app.GetDB() {
+				checks = append(checks, newDBHealthCheck(name, dbs[name]))
+			}
+		}
+	}
+
+	if app.GetDB() != nil && app.GetConfiguration().GetBoolF("HEALTH_CHECK_MIGRATIONS", true) {
+		checks = append(checks, newMigrationsHealthCheck())
+	}
+
+	return append(checks, app.GetHealthChecks()...)
+}
+
+// Run one check with its timeout, a check that doesn't return in time is reported as failed
+func runHealthCheck(ctx context.Context, app App, check *HealthCheck, defaultTimeout time.Duration) *HealthCheckResult {
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+
+		done <- check.Check(ctx, app)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timeout after %s", timeout)
+	}
+
+	result := HealthCheckResult{
+		Name:      check.Name,
+		Status:    HealthStatusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+
+	if check.Details != nil {
+		result.Details = check.Details(app)
+	}
+
+	if err != nil {
+		result.Status = HealthStatusError
+		result.Error = err.Error()
+
+		logrus.WithFields(logrus.Fields{
+			"check": check.Name,
+			"error": err.Error(),
+		}).Warn("bolo.runHealthCheck check failed")
+	}
+
+	return &result
+}
+
+// HealthLiveHandler - Liveness probe, returns ok while the process can serve requests
+func HealthLiveHandler(c echo.Context) error {
+	return c.JSON(http.StatusOK, &HealthResponse{Status: HealthStatusOK})
+}
+
+// Readiness probe, returns 503 if one dependency check fails. The errors and details of the checks are logged and
+// only returned to the requests allowed to read the metrics, the public response has the status of each check
+func healthReadyHandler(app App) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		checks := getReadinessChecks(app)
+		defaultTimeout := time.Duration(app.GetConfiguration().GetInt64F("HEALTH_CHECK_TIMEOUT", 5)) * time.Second
+
+		resp := HealthResponse{
+			Status: HealthStatusOK,
+			Checks: make([]*HealthCheckResult, len(checks)),
+		}
+
+		var wg sync.WaitGroup
+		for i, check := range checks {
+			wg.Add(1)
+			go func(i int, check *HealthCheck) {
+				defer wg.Done()
+				resp.Checks[i] = runHealthCheck(c.Request().Context(), app, check, defaultTimeout)
+			}(i, check)
+		}
+		wg.Wait()
+
+		status := http.StatusOK
+		for _, r := range resp.Checks {
+			if r.Status != HealthStatusOK {
+				resp.Status = HealthStatusError
+				status = http.StatusServiceUnavailable
+			}
+		}
+
+		if !isMetricsRequestAllowed(app, c) {
+			for _, r := range resp.Checks {
+				r.Error = ""
+				r.Details = nil
+			}
+		}
+
+		return c.JSON(status, &resp)
+	}
+}
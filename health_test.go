@@ -0,0 +1,110 @@
+package bolo_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-bolo/bolo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthEndpoints(t *testing.T) {
+	app := GetTestApp()
+	err := app.Bootstrap()
+	assert.Nil(t, err)
+
+	// requests from one METRICS_ALLOWED_IPS address get the check errors and details
+	serveFrom := func(url, remoteAddr string) (*httptest.ResponseRecorder, *bolo.HealthResponse) {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		app.GetRouter().ServeHTTP(rec, req)
+
+		resp := bolo.HealthResponse{}
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+
+		return rec, &resp
+	}
+
+	serve := func(url string) (*httptest.ResponseRecorder, *bolo.HealthResponse) {
+		return serveFrom(url, "127.0.0.1:41000")
+	}
+
+	getCheck := func(resp *bolo.HealthResponse, name string) *bolo.HealthCheckResult {
+		for _, c := range resp.Checks {
+			if c.Name == name {
+				return c
+			}
+		}
+		return nil
+	}
+
+	t.Run("live should return ok", func(t *testing.T) {
+		rec, resp := serve("/health/live")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, bolo.HealthStatusOK, resp.Status)
+	})
+
+	t.Run("ready should fail with pending migrations", func(t *testing.T) {
+		rec, resp := serve("/health/ready")
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Equal(t, bolo.HealthStatusOK, getCheck(resp, "db:default").Status)
+		assert.Contains(t, getCheck(resp, "db:default").Details, "openConnections")
+		if assert.NotNil(t, getCheck(resp, "migrations")) {
+			assert.Contains(t, getCheck(resp, "migrations").Error, "bolo has 3 pending")
+		}
+	})
+
+	t.Run("ready should not return the errors and details to public requests", func(t *testing.T) {
+		rec, resp := serveFrom("/health/ready", "203.0.113.10:41000")
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Equal(t, bolo.HealthStatusError, resp.Status)
+		assert.NotContains(t, rec.Body.String(), "pending")
+		if assert.NotNil(t, getCheck(resp, "migrations")) {
+			assert.Equal(t, bolo.HealthStatusError, getCheck(resp, "migrations").Status)
+			assert.Empty(t, getCheck(resp, "migrations").Error)
+		}
+		assert.Nil(t, getCheck(resp, "db:default").Details)
+	})
+
+	os.Setenv("HEALTH_CHECK_MIGRATIONS", "false")
+	defer os.Unsetenv("HEALTH_CHECK_MIGRATIONS")
+
+	app.AddHealthCheck(&bolo.HealthCheck{
+		Name: "search",
+		Check: func(ctx context.Context, app bolo.App) error {
+			return nil
+		},
+	})
+
+	t.Run("ready should run plugin checks", func(t *testing.T) {
+		rec, resp := serve("/health/ready")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, bolo.HealthStatusOK, resp.Status)
+		assert.Equal(t, bolo.HealthStatusOK, getCheck(resp, "search").Status)
+		assert.Nil(t, getCheck(resp, "migrations"))
+	})
+
+	t.Run("ready should fail checks after the timeout", func(t *testing.T) {
+		app.AddHealthCheck(&bolo.HealthCheck{
+			Name:    "slow",
+			Timeout: 10 * time.Millisecond,
+			Check: func(ctx context.Context, app bolo.App) error {
+				<-ctx.Done()
+				time.Sleep(50 * time.Millisecond)
+				return nil
+			},
+		})
+
+		rec, resp := serve("/health/ready")
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Equal(t, bolo.HealthStatusError, resp.Status)
+		assert.Equal(t, "timeout after 10ms", getCheck(resp, "slow").Error)
+		assert.Equal(t, bolo.HealthStatusOK, getCheck(resp, "search").Status)
+	})
+}
@@ -1,26 +1,19 @@
 package helpers
 
 import (
-	"strconv"
-
-	"github.com/cuducos/go-cnpj"
+	"github.com/go-bolo/bolo/documents"
 )
 
-// FormatCNPJ -
+// FormatCNPJ - Format one CNPJ as 00.000.000/0000-00
+//
+// Deprecated: use documents.MaskCNPJ
 func FormatCNPJ(cnpjNumbers string) string {
-	return cnpj.Mask(cnpjNumbers)
+	return documents.MaskCNPJ(cnpjNumbers)
 }
 
+// UnmaskCNPJ - Remove the CNPJ mask, keeping the leading zeros and the letters of alphanumeric CNPJs
+//
+// Deprecated: use documents.UnmaskCNPJ
 func UnmaskCNPJ(CNPJ string) string {
-	intCNPJ, err := strconv.Atoi(cnpj.Unmask(CNPJ))
-	if err != nil {
-		return ""
-	}
-
-	result := strconv.Itoa(intCNPJ)
-	if len(result) < 3 {
-		return ""
-	}
-
-	return result
+	return documents.UnmaskCNPJ(CNPJ)
 }
@@ -5,16 +5,18 @@ import (
 	"time"
 
 	"github.com/go-bolo/bolo/configuration"
+	"github.com/go-bolo/bolo/format"
+	"github.com/go-bolo/clock"
 )
 
-func FormatDate(date *time.Time, format string) string {
-	timeZone := configuration.GetEnv("SITE_TIMEZONE", "")
-	loc, err := time.LoadLocation(timeZone)
+// FormatDate - Format the date in the SITE_TIMEZONE, returns one error if the timezone is invalid
+func FormatDate(date *time.Time, layout string) (string, error) {
+	loc, err := format.LoadLocation(configuration.GetEnv("SITE_TIMEZONE", ""))
 	if err != nil {
-		panic(err)
+		return "", err
 	}
 
-	return date.In(loc).Format(format)
+	return date.In(loc).Format(layout), nil
 }
 
 func ExtractYearFromText(text string) string {
@@ -27,13 +29,8 @@ func ExtractYearFromText(text string) string {
 	return ""
 }
 
-func FormatCurrencyDate(format string) string {
-	date := time.Now()
-	timeZone := configuration.GetEnv("SITE_TIMEZONE", "")
-	loc, err := time.LoadLocation(timeZone)
-	if err != nil {
-		return ""
-	}
-
-	return date.In(loc).Format(format)
+// FormatCurrencyDate - Format the clock current date in the SITE_TIMEZONE, use the App.GetClock() clock
+func FormatCurrencyDate(c clock.Clock, layout string) (string, error) {
+	now := c.Now()
+	return FormatDate(&now, layout)
 }
@@ -2,6 +2,9 @@ package helpers
 
 import (
 	"testing"
+	"time"
+
+	"github.com/go-bolo/clock"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -27,15 +30,30 @@ func TestExtractYearFromText(t *testing.T) {
 }
 
 func TestFormatCurrencyDate(t *testing.T) {
-	// t.Run("Should return a currency date", func(t *testing.T) {
-	// 	layout := "02-01-2006"
-	// 	FormatCurrencyDate(layout)
-	// })
-
-	t.Run("Should return error with invalid format", func(t *testing.T) {
-		layout := ""
-		result := FormatCurrencyDate(layout)
-		assert.Equal(t, "", result)
+	c := clock.NewMock()
+	c.Set(time.Date(2023, 7, 16, 1, 30, 0, 0, time.UTC))
+
+	t.Run("Should return the clock date in the site timezone", func(t *testing.T) {
+		t.Setenv("SITE_TIMEZONE", "America/Sao_Paulo")
+
+		result, err := FormatCurrencyDate(c, "02-01-2006 15:04")
+		assert.Nil(t, err)
+		assert.Equal(t, "15-07-2023 22:30", result)
+	})
+
+	t.Run("Should return the clock date in UTC without site timezone", func(t *testing.T) {
+		t.Setenv("SITE_TIMEZONE", "")
+
+		result, err := FormatCurrencyDate(c, "02-01-2006")
+		assert.Nil(t, err)
+		assert.Equal(t, "16-07-2023", result)
 	})
 
+	t.Run("Should return error with invalid site timezone", func(t *testing.T) {
+		t.Setenv("SITE_TIMEZONE", "Invalid/Zone")
+
+		result, err := FormatCurrencyDate(c, "02-01-2006")
+		assert.NotNil(t, err)
+		assert.Equal(t, "", result)
+	})
 }
@@ -1,30 +1,11 @@
 package helpers
 
 import (
+	"github.com/go-bolo/bolo/format"
 	"github.com/shopspring/decimal"
 )
 
+// FormatDecimalWithDots - Format one decimal with dots as thousands separators and comma as decimal separator, ex: 1.234,5
 func FormatDecimalWithDots(n decimal.Decimal) string {
-	in := n.String()
-	numOfDigits := len(in)
-	if n.IsNegative() {
-		numOfDigits-- // First character is the - sign (not a digit)
-	}
-	numOfCommas := (numOfDigits - 1) / 3
-
-	out := make([]byte, len(in)+numOfCommas)
-	if n.IsNegative() {
-		in, out[0] = in[1:], '-'
-	}
-
-	for i, j, k := len(in)-1, len(out)-1, 0; ; i, j = i-1, j-1 {
-		out[j] = in[i]
-		if i == 0 {
-			return string(out)
-		}
-		if k++; k == 3 {
-			j, k = j-1, 0
-			out[j] = '.'
-		}
-	}
+	return format.FormatNumber(n, -1, ",", ".")
 }
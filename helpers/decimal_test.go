@@ -0,0 +1,28 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatDecimalWithDots(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "0", want: "0"},
+		{value: "999", want: "999"},
+		{value: "1000", want: "1.000"},
+		{value: "1234567", want: "1.234.567"},
+		{value: "-1234567", want: "-1.234.567"},
+		{value: "1234.5", want: "1.234,5"},
+		{value: "-0.25", want: "-0,25"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.want, FormatDecimalWithDots(decimal.RequireFromString(tt.value)))
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package http_client
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-bolo/bolo/tracing"
+)
+
+// TracingHTTPClient - Create client spans for requests with one span in the request context and send the W3C traceparent header
+type TracingHTTPClient struct {
+	Client CustomHTTPClient
+}
+
+func (c *TracingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	ctx, span := tracing.StartSpan(req.Context(), "HTTP "+req.Method, tracing.SpanKindClient)
+	if span == nil {
+		return c.Client.Do(req)
+	}
+	defer span.End()
+
+	req = req.Clone(ctx)
+	if req.Header == nil {
+		req.Header = http.Header{}
+	}
+	tracing.Inject(ctx, req.Header)
+
+	span.SetAttribute("http.method", req.Method)
+	span.SetAttribute("http.url", req.URL.Redacted())
+
+	res, err := c.Client.Do(req)
+	if err != nil {
+		span.SetError(err)
+		return res, err
+	}
+
+	span.SetAttribute("http.status_code", res.StatusCode)
+	if res.StatusCode >= 500 {
+		span.SetStatus(tracing.StatusError, strconv.Itoa(res.StatusCode))
+	}
+
+	return res, nil
+}
@@ -20,5 +20,5 @@ func Init() {
 	httpClientTimeout := configuration.GetInt64Env("HTTP_CLIENT_TIMEOUT", 120)
 
 	timeout := time.Second * time.Duration(httpClientTimeout)
-	HttpClient = &http.Client{Timeout: timeout}
+	HttpClient = &TracingHTTPClient{Client: &http.Client{Timeout: timeout}}
 }
@@ -0,0 +1,153 @@
+package bolo
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-bolo/bolo/format"
+	"github.com/go-bolo/bolo/i18n"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+//go:embed translations
+var boloTranslations embed.FS
+
+// I18nPlugin - Optional Pluginer method with the plugin message catalogs, [locale].json or [locale].po files in the FS root.
+// Messages in the app LOCALES_FOLDER override the plugin messages
+type I18nPlugin interface {
+	GetLocales() fs.FS
+}
+
+func getLocaleCookieName(app App) string {
+	return app.GetConfiguration().GetF("LOCALE_COOKIE_NAME", "bolo_locale")
+}
+
+// Load the format package messages, the plugin catalogs and the app LOCALES_FOLDER files
+func loadTranslations(app App) error {
+	catalog := app.GetI18n()
+
+	if err := catalog.LoadFS(format.Messages, "."); err != nil {
+		return err
+	}
+
+	for _, p := range app.GetPlugins() {
+		ip, ok := p.(I18nPlugin)
+		if !ok || ip.GetLocales() == nil {
+			continue
+		}
+
+		if err := catalog.LoadFS(ip.GetLocales(), "."); err != nil {
+			return err
+		}
+	}
+
+	dir := app.GetConfiguration().GetF("LOCALES_FOLDER", "./locales")
+	if _, err := os.Stat(dir); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"dir": dir,
+		}).Debug("bolo.loadTranslations locales folder not found")
+		return nil
+	}
+
+	return catalog.LoadFS(os.DirFS(dir), ".")
+}
+
+// Get the request locale candidates in preference order: URL prefix, user language, locale cookie and Accept-Language
+func getLocaleCandidates(ctx *RequestContext) []string {
+	candidates := []string{}
+
+	if locale := i18n.LocaleFromContext(ctx.Request().Context()); locale != "" {
+		candidates = append(candidates, locale)
+	}
+
+	if ctx.AuthenticatedUser != nil && ctx.AuthenticatedUser.GetLanguage() != "" {
+		candidates = append(candidates, ctx.AuthenticatedUser.GetLanguage())
+	}
+
+	if cookie, err := ctx.Cookie(getLocaleCookieName(ctx.App)); err == nil && cookie.Value != "" {
+		candidates = append(candidates, cookie.Value)
+	}
+
+	specs := ParseAccept(ctx.Request().Header, "Accept-Language")
+	sort.SliceStable(specs, func(i, j int) bool {
+		return specs[i].Q > specs[j].Q
+	})
+
+	for _, spec := range specs {
+		if spec.Q > 0 && spec.Value != "*" {
+			candidates = append(candidates, spec.Value)
+		}
+	}
+
+	return candidates
+}
+
+// GetLocale - Get the request locale, the first candidate with messages in the app catalog or the DEFAULT_LANGUAGE
+func (r *RequestContext) GetLocale() string {
+	if r.locale == "" {
+		r.locale = r.App.GetI18n().Match(getLocaleCandidates(r)...)
+	}
+
+	return r.locale
+}
+
+// SetLocale - Set the locale of the current request, use SetLocaleCookie to keep it in the next requests
+func (r *RequestContext) SetLocale(locale string) {
+	r.locale = i18n.NormalizeLocale(locale)
+	r.formatter = nil
+}
+
+// SetLocaleCookie - Set the locale of the current and next requests of the client
+func (r *RequestContext) SetLocaleCookie(locale string) {
+	r.SetLocale(locale)
+
+	r.SetCookie(&http.Cookie{
+		Name:     getLocaleCookieName(r.App),
+		Value:    r.locale,
+		Path:     "/",
+		MaxAge:   365 * 24 * 60 * 60,
+		HttpOnly: true,
+		Secure:   r.Protocol == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// T - Translate one message to the request locale, see i18n.Catalog.T
+func (r *RequestContext) T(key string, args ...interface{}) string {
+	return r.App.GetI18n().T(r.GetLocale(), key, args...)
+}
+
+// Middleware that removes the locale prefix of the URL path, ex: /pt-br/about to /about with the pt-BR locale
+func localeURLPrefixMiddleware(app App) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			prefix, rest, _ := strings.Cut(strings.TrimPrefix(req.URL.Path, "/"), "/")
+			if prefix == "" || !app.GetI18n().HasLocale(prefix) {
+				return next(c)
+			}
+
+			req.URL.Path = "/" + rest
+			req.URL.RawPath = ""
+			c.SetRequest(req.WithContext(i18n.ContextWithLocale(req.Context(), i18n.NormalizeLocale(prefix))))
+
+			return next(c)
+		}
+	}
+}
+
+// Template function that translates one message to the request locale, ex: {{ t .Ctx "items" 2 }}
+func translate(ctx *RequestContext, key string, args ...interface{}) string {
+	return ctx.T(key, args...)
+}
+
+// Template function with the request locale, ex: <html lang="{{ locale .Ctx }}">
+func locale(ctx *RequestContext) string {
+	return ctx.GetLocale()
+}
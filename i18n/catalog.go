@@ -0,0 +1,322 @@
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// Plural categories of the JSON messages, see https://cldr.unicode.org/index/cldr-spec/plural-rules
+const (
+	PluralZero  = "zero"
+	PluralOne   = "one"
+	PluralTwo   = "two"
+	PluralFew   = "few"
+	PluralMany  = "many"
+	PluralOther = "other"
+)
+
+// Message - One translated text, with optional plural forms
+type Message struct {
+	// Text without plural or the "other" plural form
+	Text string
+	// Plural forms by CLDR category, from JSON files
+	Forms map[string]string
+	// Plural forms by gettext index, from PO files
+	Plurals []string
+}
+
+// Catalog - Translated messages by locale, locales are BCP 47 tags, ex: en or pt-BR
+type Catalog struct {
+	mu            sync.RWMutex
+	defaultLocale string
+	messages      map[string]map[string]*Message
+	// gettext plural formula of the PO files by locale
+	pluralFuncs map[string]PluralFunc
+}
+
+func New(defaultLocale string) *Catalog {
+	return &Catalog{
+		defaultLocale: NormalizeLocale(defaultLocale),
+		messages:      map[string]map[string]*Message{},
+		pluralFuncs:   map[string]PluralFunc{},
+	}
+}
+
+// NormalizeLocale - Format one locale as BCP 47 tag, ex: pt_br to pt-BR
+func NormalizeLocale(locale string) string {
+	locale = strings.TrimSpace(strings.ReplaceAll(locale, "_", "-"))
+
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return locale
+	}
+
+	return tag.String()
+}
+
+func (c *Catalog) GetDefaultLocale() string {
+	return c.defaultLocale
+}
+
+// GetLocales - Get the locales with messages, sorted
+func (c *Catalog) GetLocales() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	locales := []string{}
+	for locale := range c.messages {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	return locales
+}
+
+func (c *Catalog) HasLocale(locale string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, ok := c.messages[NormalizeLocale(locale)]
+	return ok
+}
+
+// Set - Add or replace one message
+func (c *Catalog) Set(locale, key string, message *Message) {
+	locale = NormalizeLocale(locale)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.messages[locale] == nil {
+		c.messages[locale] = map[string]*Message{}
+	}
+	c.messages[locale][key] = message
+}
+
+// SetString - Add or replace one message without plural forms
+func (c *Catalog) SetString(locale, key, text string) {
+	c.Set(locale, key, &Message{Text: text})
+}
+
+// LoadJSON - Load one JSON object with the messages of the locale. Values are texts or objects with the plural forms:
+//
+//	{"hello": "Hello %s", "items": {"one": "%d item", "other": "%d items"}}
+func (c *Catalog) LoadJSON(locale string, data []byte) error {
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("i18n: invalid JSON messages of %s: %w", locale, err)
+	}
+
+	for key, value := range raw {
+		var text string
+		if err := json.Unmarshal(value, &text); err == nil {
+			c.SetString(locale, key, text)
+			continue
+		}
+
+		forms := map[string]string{}
+		if err := json.Unmarshal(value, &forms); err != nil {
+			return fmt.Errorf("i18n: invalid JSON message %q of %s: %w", key, locale, err)
+		}
+
+		c.Set(locale, key, &Message{Text: forms[PluralOther], Forms: forms})
+	}
+
+	return nil
+}
+
+// LoadFS - Load the [locale].json and [locale].po files in the dir, ex: locales/pt-BR.json
+func (c *Catalog) LoadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		ext := path.Ext(entry.Name())
+		if ext != ".json" && ext != ".po" {
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+
+		locale := strings.TrimSuffix(entry.Name(), ext)
+		if ext == ".json" {
+			err = c.LoadJSON(locale, data)
+		} else {
+			err = c.LoadPO(locale, data)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Match - Get the first candidate locale with messages, matching by the language if the region is not available.
+// Returns the default locale if no candidate matches
+func (c *Catalog) Match(candidates ...string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+
+		locale := NormalizeLocale(candidate)
+		if _, ok := c.messages[locale]; ok {
+			return locale
+		}
+
+		base, _, _ := strings.Cut(locale, "-")
+		if _, ok := c.messages[base]; ok {
+			return base
+		}
+
+		// one regional locale of the language, ex: pt to pt-BR
+		for _, l := range c.sortedLocales() {
+			if strings.HasPrefix(l, base+"-") {
+				return l
+			}
+		}
+	}
+
+	return c.defaultLocale
+}
+
+func (c *Catalog) sortedLocales() []string {
+	locales := make([]string, 0, len(c.messages))
+	for locale := range c.messages {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	return locales
+}
+
+// Get the message of the locale, the locale language or the default locale
+func (c *Catalog) lookup(locale, key string) (string, *Message) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	base, _, _ := strings.Cut(locale, "-")
+	for _, l := range []string{locale, base, c.defaultLocale} {
+		if m := c.messages[l][key]; m != nil {
+			return l, m
+		}
+	}
+
+	return locale, nil
+}
+
+// T - Translate the key to the locale, returns the key if the message does not exist.
+// Args are fmt verbs values, with plural messages the first integer arg selects the plural form:
+//
+//	catalog.T("pt-BR", "items", 2) // 2 itens
+func (c *Catalog) T(locale, key string, args ...interface{}) string {
+	locale = NormalizeLocale(locale)
+	l, m := c.lookup(locale, key)
+	if m == nil {
+		return key
+	}
+
+	text := m.Text
+	if len(args) > 0 && (len(m.Forms) > 0 || len(m.Plurals) > 0) {
+		if n, ok := toInt(args[0]); ok {
+			text = c.pluralText(l, m, n)
+		}
+	}
+
+	if len(args) == 0 || !strings.Contains(text, "%") {
+		return text
+	}
+
+	return fmt.Sprintf(text, args...)
+}
+
+func (c *Catalog) pluralText(locale string, m *Message, n int) string {
+	if len(m.Plurals) > 0 {
+		c.mu.RLock()
+		f := c.pluralFuncs[locale]
+		c.mu.RUnlock()
+
+		if f == nil {
+			f = defaultPluralFunc
+		}
+
+		if i := f(n); i >= 0 && i < len(m.Plurals) {
+			return m.Plurals[i]
+		}
+
+		return m.Plurals[len(m.Plurals)-1]
+	}
+
+	if n == 0 && m.Forms[PluralZero] != "" {
+		return m.Forms[PluralZero]
+	}
+
+	if text := m.Forms[PluralCategory(locale, n)]; text != "" {
+		return text
+	}
+
+	return m.Text
+}
+
+// PluralCategory - Get the CLDR plural category of one integer in the locale, ex: one or other
+func PluralCategory(locale string, n int) string {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		tag = language.English
+	}
+
+	abs := n
+	if abs < 0 {
+		abs = -abs
+	}
+
+	switch plural.Cardinal.MatchPlural(tag, abs, 0, 0, 0, 0) {
+	case plural.Zero:
+		return PluralZero
+	case plural.One:
+		return PluralOne
+	case plural.Two:
+		return PluralTwo
+	case plural.Few:
+		return PluralFew
+	case plural.Many:
+		return PluralMany
+	default:
+		return PluralOther
+	}
+}
+
+func toInt(v interface{}) (int, bool) {
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(rv.Uint()), true
+	default:
+		return 0, false
+	}
+}
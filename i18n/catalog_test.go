@@ -0,0 +1,137 @@
+package i18n_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-bolo/bolo/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPO = `# Russian messages
+msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+msgid "hello"
+msgstr "Привет, %s"
+
+#, fuzzy
+msgid "draft"
+msgstr "Черновик"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d файл"
+msgstr[1] "%d файла"
+msgstr[2] "%d файлов"
+`
+
+func TestCatalog_T(t *testing.T) {
+	c := i18n.New("en")
+	assert.Nil(t, c.LoadFS(fstest.MapFS{
+		"en.json":    {Data: []byte(`{"hello": "Hello %s", "items": {"one": "%d item", "other": "%d items"}}`)},
+		"pt-BR.json": {Data: []byte(`{"hello": "Olá %s", "items": {"zero": "Nenhum item", "one": "%d item", "other": "%d itens"}}`)},
+		"pl.json":    {Data: []byte(`{"items": {"one": "%d plik", "few": "%d pliki", "many": "%d plików", "other": "%d pliku"}}`)},
+		"ru.po":      {Data: []byte(testPO)},
+		"README.md":  {Data: []byte("not a catalog")},
+	}, "."))
+
+	assert.Equal(t, []string{"en", "pl", "pt-BR", "ru"}, c.GetLocales())
+
+	tests := []struct {
+		locale string
+		key    string
+		args   []interface{}
+		want   string
+	}{
+		{locale: "en", key: "hello", args: []interface{}{"Maria"}, want: "Hello Maria"},
+		{locale: "en", key: "items", args: []interface{}{1}, want: "1 item"},
+		{locale: "en", key: "items", args: []interface{}{0}, want: "0 items"},
+		{locale: "pt_br", key: "hello", args: []interface{}{"Maria"}, want: "Olá Maria"},
+		{locale: "pt-BR", key: "items", args: []interface{}{0}, want: "Nenhum item"},
+		{locale: "pt-BR", key: "items", args: []interface{}{int64(3)}, want: "3 itens"},
+		{locale: "pl", key: "items", args: []interface{}{3}, want: "3 pliki"},
+		{locale: "pl", key: "items", args: []interface{}{5}, want: "5 plików"},
+		{locale: "ru", key: "hello", args: []interface{}{"Мария"}, want: "Привет, Мария"},
+		{locale: "ru", key: "%d file", args: []interface{}{21}, want: "21 файл"},
+		{locale: "ru", key: "%d file", args: []interface{}{3}, want: "3 файла"},
+		{locale: "ru", key: "%d file", args: []interface{}{11}, want: "11 файлов"},
+		{locale: "ru", key: "draft", want: "draft"},
+		{locale: "pt-PT", key: "hello", args: []interface{}{"Maria"}, want: "Hello Maria"},
+		{locale: "pl", key: "hello", args: []interface{}{"Maria"}, want: "Hello Maria"},
+		{locale: "en", key: "missing", want: "missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale+" "+tt.key, func(t *testing.T) {
+			assert.Equal(t, tt.want, c.T(tt.locale, tt.key, tt.args...))
+		})
+	}
+}
+
+func TestCatalog_Match(t *testing.T) {
+	c := i18n.New("en")
+	c.SetString("en", "a", "a")
+	c.SetString("es", "a", "a")
+	c.SetString("pt-BR", "a", "a")
+
+	tests := []struct {
+		name       string
+		candidates []string
+		want       string
+	}{
+		{name: "without candidates", want: "en"},
+		{name: "exact", candidates: []string{"pt-BR"}, want: "pt-BR"},
+		{name: "normalized", candidates: []string{"pt_br"}, want: "pt-BR"},
+		{name: "base language", candidates: []string{"es-AR"}, want: "es"},
+		{name: "regional variant", candidates: []string{"pt"}, want: "pt-BR"},
+		{name: "first match", candidates: []string{"de", "", "es", "pt-BR"}, want: "es"},
+		{name: "no match", candidates: []string{"de", "fr"}, want: "en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, c.Match(tt.candidates...))
+		})
+	}
+}
+
+func TestCatalog_LoadErrors(t *testing.T) {
+	c := i18n.New("en")
+
+	assert.NotNil(t, c.LoadJSON("en", []byte(`{"items": [1]}`)))
+	assert.NotNil(t, c.LoadPO("en", []byte("msgid \"a\"\nmsgval \"b\"\n")))
+	assert.NotNil(t, c.LoadPO("en", []byte("msgid \"\"\nmsgstr \"Plural-Forms: nplurals=2; plural=(n != ;\\n\"\n")))
+}
+
+func TestParsePluralFormula(t *testing.T) {
+	tests := []struct {
+		formula string
+		want    map[int]int
+		wantErr bool
+	}{
+		{formula: "0", want: map[int]int{0: 0, 1: 0, 5: 0}},
+		{formula: "(n != 1)", want: map[int]int{0: 1, 1: 0, 2: 1}},
+		{formula: "(n > 1)", want: map[int]int{0: 0, 1: 0, 2: 1}},
+		{formula: "n==1 ? 0 : n==2 ? 1 : 2", want: map[int]int{1: 0, 2: 1, 3: 2}},
+		{formula: "!(n%10==1 && n%100!=11)", want: map[int]int{1: 0, 11: 1, 21: 0}},
+		{formula: "n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2", want: map[int]int{1: 0, 2: 1, 5: 2, 12: 2, 22: 1, 111: 2}},
+		{formula: "(n != 1", wantErr: true},
+		{formula: "n ? 1", wantErr: true},
+		{formula: "n = 1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.formula, func(t *testing.T) {
+			f, err := i18n.ParsePluralFormula(tt.formula)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			for n, want := range tt.want {
+				assert.Equal(t, want, f(n), "n=%d", n)
+			}
+		})
+	}
+}
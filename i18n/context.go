@@ -0,0 +1,20 @@
+package i18n
+
+import "context"
+
+type localeContextKey struct{}
+
+// ContextWithLocale - Get one context with the request locale, ex: to translate messages in services
+func ContextWithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeContextKey{}, locale)
+}
+
+// LocaleFromContext - Get the locale set with ContextWithLocale, empty if not set
+func LocaleFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	locale, _ := ctx.Value(localeContextKey{}).(string)
+	return locale
+}
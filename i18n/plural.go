@@ -0,0 +1,239 @@
+package i18n
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// PluralFunc - Get the plural form index of one number, like the gettext Plural-Forms formula
+type PluralFunc func(n int) int
+
+// Germanic plural, used in PO files without Plural-Forms
+var defaultPluralFunc PluralFunc = func(n int) int {
+	if n != 1 {
+		return 1
+	}
+	return 0
+}
+
+// ParsePluralFormula - Parse one gettext plural formula, ex: (n != 1) or n%10==1 && n%100!=11 ? 0 : 1
+func ParsePluralFormula(formula string) (PluralFunc, error) {
+	p := pluralParser{tokens: tokenizePluralFormula(formula)}
+
+	expr, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in plural formula", p.tokens[p.pos])
+	}
+
+	return func(n int) int {
+		v := expr(n)
+		if v < 0 {
+			return 0
+		}
+		return v
+	}, nil
+}
+
+func tokenizePluralFormula(formula string) []string {
+	tokens := []string{}
+
+	for i := 0; i < len(formula); {
+		c := formula[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == ';':
+			i++
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(formula) && formula[j] >= '0' && formula[j] <= '9' {
+				j++
+			}
+			tokens = append(tokens, formula[i:j])
+			i = j
+		case i+1 < len(formula) && containsString([]string{"==", "!=", "<=", ">=", "&&", "||"}, formula[i:i+2]):
+			tokens = append(tokens, formula[i:i+2])
+			i += 2
+		default:
+			tokens = append(tokens, string(c))
+			i++
+		}
+	}
+
+	return tokens
+}
+
+type pluralExpr func(n int) int
+
+type pluralParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *pluralParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *pluralParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *pluralParser) ternary() (pluralExpr, error) {
+	cond, err := p.binary(0)
+	if err != nil || p.peek() != "?" {
+		return cond, err
+	}
+	p.next()
+
+	a, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	if p.next() != ":" {
+		return nil, fmt.Errorf("expected : in plural formula")
+	}
+	b, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(n int) int {
+		if cond(n) != 0 {
+			return a(n)
+		}
+		return b(n)
+	}, nil
+}
+
+// binary operators by precedence, lowest first
+var pluralOperators = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", ">", "<=", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *pluralParser) binary(level int) (pluralExpr, error) {
+	if level == len(pluralOperators) {
+		return p.unary()
+	}
+
+	left, err := p.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op := p.peek()
+		if !containsString(pluralOperators[level], op) {
+			return left, nil
+		}
+		p.next()
+
+		right, err := p.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		left = pluralOperation(op, left, right)
+	}
+}
+
+func pluralOperation(op string, a, b pluralExpr) pluralExpr {
+	boolInt := func(v bool) int {
+		if v {
+			return 1
+		}
+		return 0
+	}
+
+	return func(n int) int {
+		x, y := a(n), b(n)
+
+		switch op {
+		case "||":
+			return boolInt(x != 0 || y != 0)
+		case "&&":
+			return boolInt(x != 0 && y != 0)
+		case "==":
+			return boolInt(x == y)
+		case "!=":
+			return boolInt(x != y)
+		case "<":
+			return boolInt(x < y)
+		case ">":
+			return boolInt(x > y)
+		case "<=":
+			return boolInt(x <= y)
+		case ">=":
+			return boolInt(x >= y)
+		case "+":
+			return x + y
+		case "-":
+			return x - y
+		case "*":
+			return x * y
+		case "/":
+			if y == 0 {
+				return 0
+			}
+			return x / y
+		default:
+			if y == 0 {
+				return 0
+			}
+			return x % y
+		}
+	}
+}
+
+func (p *pluralParser) unary() (pluralExpr, error) {
+	switch t := p.next(); {
+	case t == "!":
+		e, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(n int) int {
+			if e(n) == 0 {
+				return 1
+			}
+			return 0
+		}, nil
+	case t == "n":
+		return func(n int) int { return n }, nil
+	case t == "(":
+		e, err := p.ternary()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("expected ) in plural formula")
+		}
+		return e, nil
+	default:
+		v, err := strconv.Atoi(t)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected %q in plural formula", t)
+		}
+		return func(n int) int { return v }, nil
+	}
+}
+
+func containsString(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package i18n
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type poEntry struct {
+	id       string
+	idPlural string
+	str      string
+	plurals  map[int]*string
+	fuzzy    bool
+}
+
+// LoadPO - Load one gettext PO file with the messages of the locale, the msgid is the message key.
+// Plural messages use the Plural-Forms formula of the file header
+func (c *Catalog) LoadPO(locale string, data []byte) error {
+	entries, err := parsePO(data)
+	if err != nil {
+		return fmt.Errorf("i18n: invalid PO messages of %s: %w", locale, err)
+	}
+
+	for _, e := range entries {
+		if e.id == "" {
+			if err := c.loadPOHeader(locale, e.str); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if e.fuzzy {
+			continue
+		}
+
+		if e.idPlural == "" {
+			if e.str != "" {
+				c.SetString(locale, e.id, e.str)
+			}
+			continue
+		}
+
+		plurals := make([]string, len(e.plurals))
+		for i, text := range e.plurals {
+			if i < len(plurals) {
+				plurals[i] = *text
+			}
+		}
+		if len(plurals) == 0 || plurals[0] == "" {
+			continue
+		}
+
+		c.Set(locale, e.id, &Message{Text: plurals[len(plurals)-1], Plurals: plurals})
+	}
+
+	return nil
+}
+
+func (c *Catalog) loadPOHeader(locale, header string) error {
+	for _, line := range strings.Split(header, "\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(name) != "Plural-Forms" {
+			continue
+		}
+
+		for _, part := range strings.Split(value, ";") {
+			key, formula, _ := strings.Cut(strings.TrimSpace(part), "=")
+			if key != "plural" {
+				continue
+			}
+
+			f, err := ParsePluralFormula(formula)
+			if err != nil {
+				return fmt.Errorf("i18n: invalid Plural-Forms of %s: %w", locale, err)
+			}
+
+			c.mu.Lock()
+			c.pluralFuncs[NormalizeLocale(locale)] = f
+			c.mu.Unlock()
+		}
+	}
+
+	return nil
+}
+
+func parsePO(data []byte) ([]*poEntry, error) {
+	entries := []*poEntry{}
+	entry := &poEntry{plurals: map[int]*string{}}
+	hasEntry := false
+	// field of the last keyword, continuation lines are appended to it
+	var current *string
+
+	flush := func() {
+		if hasEntry {
+			entries = append(entries, entry)
+		}
+		entry = &poEntry{plurals: map[int]*string{}}
+		hasEntry = false
+		current = nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, "#,"):
+			if hasEntry {
+				flush()
+			}
+			entry.fuzzy = strings.Contains(line, "fuzzy")
+		case strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, `"`):
+			if current == nil {
+				return nil, fmt.Errorf("line %d: string without keyword", n)
+			}
+
+			s, err := strconv.Unquote(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+			*current += s
+		default:
+			keyword, value, ok := strings.Cut(line, " ")
+			if !ok {
+				return nil, fmt.Errorf("line %d: invalid line %q", n, line)
+			}
+
+			s, err := strconv.Unquote(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+
+			// one msgid or msgctxt after one msgstr starts a new entry without blank line
+			if (keyword == "msgid" || keyword == "msgctxt") && (entry.str != "" || len(entry.plurals) > 0) {
+				flush()
+			}
+
+			switch {
+			case keyword == "msgctxt":
+				// contexts are not supported, the message key is the msgid
+				current = new(string)
+			case keyword == "msgid":
+				entry.id = s
+				current = &entry.id
+			case keyword == "msgid_plural":
+				entry.idPlural = s
+				current = &entry.idPlural
+			case keyword == "msgstr":
+				entry.str = s
+				current = &entry.str
+			case strings.HasPrefix(keyword, "msgstr[") && strings.HasSuffix(keyword, "]"):
+				i, err := strconv.Atoi(keyword[7 : len(keyword)-1])
+				if err != nil || i < 0 {
+					return nil, fmt.Errorf("line %d: invalid plural index %q", n, keyword)
+				}
+
+				entry.plurals[i] = &s
+				current = entry.plurals[i]
+			default:
+				return nil, fmt.Errorf("line %d: unknown keyword %q", n, keyword)
+			}
+
+			hasEntry = true
+		}
+	}
+	flush()
+
+	return entries, scanner.Err()
+}
@@ -0,0 +1,163 @@
+package bolo_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-bolo/bolo"
+	"github.com/go-bolo/bolo/i18n"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestContext_GetLocale(t *testing.T) {
+	app := GetTestApp()
+	assert.Nil(t, app.Bootstrap())
+
+	app.GetI18n().SetString("es", "hello", "Hola %s")
+
+	tests := []struct {
+		name           string
+		prefix         string
+		userLanguage   string
+		cookie         string
+		acceptLanguage string
+		want           string
+		wantHello      string
+	}{
+		{name: "default", want: "en", wantHello: "hello"},
+		{name: "accept language by quality", acceptLanguage: "de;q=0.9, pt-BR;q=0.8, es;q=0.5", want: "pt-BR", wantHello: "hello"},
+		{name: "accept language by base language", acceptLanguage: "es-AR", want: "es", wantHello: "Hola Maria"},
+		{name: "cookie before accept language", cookie: "es", acceptLanguage: "pt-BR", want: "es", wantHello: "Hola Maria"},
+		{name: "user before cookie", userLanguage: "pt_BR", cookie: "es", want: "pt-BR", wantHello: "hello"},
+		{name: "url prefix before user", prefix: "es", userLanguage: "pt-BR", want: "es", wantHello: "Hola Maria"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.prefix != "" {
+				req = req.WithContext(i18n.ContextWithLocale(req.Context(), tt.prefix))
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "bolo_locale", Value: tt.cookie})
+			}
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			c := app.GetRouter().NewContext(req, httptest.NewRecorder())
+			ctx := bolo.NewRequestContext(&bolo.RequestContextOpts{App: app, EchoContext: c})
+			if tt.userLanguage != "" {
+				ctx.AuthenticatedUser = &UserModel{Language: tt.userLanguage}
+			}
+
+			assert.Equal(t, tt.want, ctx.GetLocale())
+			assert.Equal(t, tt.wantHello, ctx.T("hello", "Maria"))
+		})
+	}
+}
+
+func TestRequestContext_SetLocaleCookie(t *testing.T) {
+	app := GetTestApp()
+	assert.Nil(t, app.Bootstrap())
+
+	rec := httptest.NewRecorder()
+	c := app.GetRouter().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	ctx := bolo.NewRequestContext(&bolo.RequestContextOpts{App: app, EchoContext: c})
+
+	ctx.SetLocaleCookie("pt_br")
+
+	assert.Equal(t, "pt-BR", ctx.GetLocale())
+	assert.Equal(t, "Não encontrado", ctx.T("bolo.error.notFound"))
+	assert.Contains(t, rec.Header().Get(echo.HeaderSetCookie), "bolo_locale=pt-BR")
+}
+
+func TestLocaleURLPrefix(t *testing.T) {
+	os.Setenv("I18N_URL_PREFIX", "true")
+	defer os.Unsetenv("I18N_URL_PREFIX")
+
+	app := GetTestApp()
+	assert.Nil(t, app.Bootstrap())
+
+	app.SetRoute(app.GetRouterGroup("main"), &bolo.Route{
+		Method: http.MethodGet,
+		Path:   "locale-test",
+		Action: func(c echo.Context) error {
+			ctx := c.(*bolo.RequestContext)
+			return c.String(http.StatusOK, ctx.GetLocale()+" "+ctx.T("bolo.error.forbidden"))
+		},
+	})
+
+	tests := []struct {
+		name     string
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{name: "without prefix", path: "/locale-test", wantCode: http.StatusOK, wantBody: "en Forbidden"},
+		{name: "with prefix", path: "/pt-br/locale-test", wantCode: http.StatusOK, wantBody: "pt-BR Acesso restrito"},
+		{name: "unknown prefix", path: "/xx/locale-test", wantCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			app.GetRouter().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestErrorPages_UserLanguage(t *testing.T) {
+	app := GetTestApp()
+	app.SetUserLoader(func(ctx *bolo.RequestContext, userID string) (bolo.UserInterface, error) {
+		return &UserModel{ID: userID, Active: true, Language: "pt-BR"}, nil
+	})
+	setTestTemplates(t, map[string]string{
+		"site/html.html":            `{{ .Ctx.Content }}`,
+		"site/layouts/default.html": `{{ .Ctx.Title }}`,
+		"site/403.html":             ``,
+		"site/404.html":             ``,
+	})
+	assert.Nil(t, app.Bootstrap())
+
+	app.SetRoute(app.GetRouterGroup("main"), &bolo.Route{
+		Method:     http.MethodGet,
+		Path:       "private",
+		Permission: "view_private",
+		Action: func(c echo.Context) error {
+			return c.String(http.StatusOK, "private")
+		},
+	})
+
+	token, _ := bolo.NewAuthenticationToken(app, "10", time.Hour)
+
+	tests := []struct {
+		name     string
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{name: "forbidden", path: "/private", wantCode: http.StatusForbidden, wantBody: "Acesso restrito"},
+		{name: "not found", path: "/not-found", wantCode: http.StatusNotFound, wantBody: "Não encontrado"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set(echo.HeaderAccept, "text/html")
+			req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+			req.Header.Set("Accept-Language", "es")
+			rec := httptest.NewRecorder()
+			app.GetRouter().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, tt.wantBody, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package bolo
+
+import (
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-bolo/bolo/metrics"
+	"github.com/gookit/event"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Route label used in requests that don't match one route, avoids one serie for each unknown url
+const metricsNotFoundRoute = "notFound"
+
+// Middleware that counts requests and observes request latencies by route template
+func metricsMiddleware(app App) echo.MiddlewareFunc {
+	registry := app.GetMetrics()
+
+	requests := registry.NewCounter("bolo_http_requests_total", "HTTP requests by method, route and status.", "method", "route", "status")
+	duration := registry.NewHistogram("bolo_http_request_duration_seconds", "HTTP request latencies by method and route.", nil, "method", "route")
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.(*RequestContext)
+			start := time.Now()
+
+			// run the error handler now to count the final response status
+			err := handleErrorOnce(ctx, next(ctx))
+
+			status := c.Response().Status
+			route := c.Path()
+			if route == "" {
+				route = metricsNotFoundRoute
+			}
+
+			method := c.Request().Method
+
+			requests.Inc(method, route, strconv.Itoa(status))
+			duration.Observe(time.Since(start).Seconds(), method, route)
+
+			return err
+		}
+	}
+}
+
+// Count errors handled by the CustomHTTPErrorHandler by response status
+func newHTTPErrorsMetric(app App) *metrics.Counter {
+	return app.GetMetrics().NewCounter("bolo_http_errors_total", "Errors handled by the HTTP error handler by status.", "status")
+}
+
+// Register gorm callbacks that observe query durations of one database
+func registerDBMetricsCallbacks(app App, dbName string, db *gorm.DB) error {
+	duration := app.GetMetrics().NewHistogram("bolo_db_query_duration_seconds", "Database query durations by database, operation and table.", nil, "db", "operation", "table")
+
+	before := func(tx *gorm.DB) {
+		tx.InstanceSet("bolo:metricsStart", time.Now())
+	}
+
+	after := func(operation string) func(tx *gorm.DB) {
+		return func(tx *gorm.DB) {
+			v, ok := tx.InstanceGet("bolo:metricsStart")
+			if !ok {
+				return
+			}
+
+			table := ""
+			if tx.Statement != nil {
+				table = tx.Statement.Table
+			}
+
+			duration.Observe(time.Since(v.(time.Time)).Seconds(), dbName, operation, table)
+		}
+	}
+
+	cb := db.Callback()
+
+	if err := cb.Create().Before("gorm:create").Register("bolo:metrics_before_create", before); err != nil {
+		return err
+	}
+	if err := cb.Create().After("gorm:create").Register("bolo:metrics_after_create", after("create")); err != nil {
+		return err
+	}
+	if err := cb.Query().Before("gorm:query").Register("bolo:metrics_before_query", before); err != nil {
+		return err
+	}
+	if err := cb.Query().After("gorm:query").Register("bolo:metrics_after_query", after("query")); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("bolo:metrics_before_update", before); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Register("bolo:metrics_after_update", after("update")); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("bolo:metrics_before_delete", before); err != nil {
+		return err
+	}
+	if err := cb.Delete().After("gorm:delete").Register("bolo:metrics_after_delete", after("delete")); err != nil {
+		return err
+	}
+	if err := cb.Row().Before("gorm:row").Register("bolo:metrics_before_row", before); err != nil {
+		return err
+	}
+	if err := cb.Row().After("gorm:row").Register("bolo:metrics_after_row", after("row")); err != nil {
+		return err
+	}
+	if err := cb.Raw().Before("gorm:raw").Register("bolo:metrics_before_raw", before); err != nil {
+		return err
+	}
+
+	return cb.Raw().After("gorm:raw").Register("bolo:metrics_after_raw", after("raw"))
+}
+
+// Count triggered events by name with one wildcard listener
+func registerEventMetricsListener(app App) {
+	triggers := app.GetMetrics().NewCounter("bolo_events_total", "Events triggered in the app event manager by name.", "event")
+
+	app.GetEvents().On(event.Wildcard, event.ListenerFunc(func(e event.Event) error {
+		triggers.Inc(e.Name())
+		return nil
+	}), event.Min)
+}
+
+// Update migration gauges from the bolo_migrations table on each metrics collect
+func registerMigrationMetricsCollector(app App) {
+	registry := app.GetMetrics()
+
+	version := registry.NewGauge("bolo_migration_version", "Last migration version ran by plugin.", "plugin")
+	pending := registry.NewGauge("bolo_migration_pending", "Migrations not ran yet by plugin.", "plugin")
+	failed := registry.NewGauge("bolo_migration_failed", "1 if the last migration of the plugin failed.", "plugin")
+
+	registry.OnCollect(func() {
+		if app.GetDB() == nil {
+			return
+		}
+
+		m := NewMigrationEngine(&NewMigrationEngineOpts{App: app})
+		list, err := m.GetStatus()
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"error": err.Error(),
+			}).Debug("bolo.registerMigrationMetricsCollector error on get migrations status")
+			return
+		}
+
+		version.Reset()
+		pending.Reset()
+		failed.Reset()
+
+		for _, status := range list {
+			version.Set(float64(status.Version), status.PluginName)
+			pending.Set(float64(status.Pending), status.PluginName)
+			if status.LastError != "" {
+				failed.Set(1, status.PluginName)
+			} else {
+				failed.Set(0, status.PluginName)
+			}
+		}
+	})
+}
+
+// Update connection pool gauges and wait counters from the database/sql stats on each metrics collect
+func registerDBPoolMetricsCollector(app App) {
+	registry := app.GetMetrics()
+
+	open := registry.NewGauge("bolo_db_pool_open_connections", "Open connections by database.", "db")
+	inUse := registry.NewGauge("bolo_db_pool_in_use_connections", "Connections in use by database.", "db")
+	idle := registry.NewGauge("bolo_db_pool_idle_connections", "Idle connections by database.", "db")
+	maxOpen := registry.NewGauge("bolo_db_pool_max_open_connections", "Max open connections by database, 0 is unlimited.", "db")
+	waitCount := registry.NewCounter("bolo_db_pool_wait_count_total", "Total connections waited for by database.", "db")
+	waitDuration := registry.NewCounter("bolo_db_pool_wait_duration_seconds_total", "Total time blocked waiting for one connection by database.", "db")
+
+	registry.OnCollect(func() {
+		for name, stats := range app.GetDBStats() {
+			open.Set(float64(stats.OpenConnections), name)
+			inUse.Set(float64(stats.InUse), name)
+			idle.Set(float64(stats.Idle), name)
+			maxOpen.Set(float64(stats.MaxOpenConnections), name)
+			waitCount.SetTotal(float64(stats.WaitCount), name)
+			waitDuration.SetTotal(stats.WaitDuration.Seconds(), name)
+		}
+	})
+}
+
+// Check if the request can read the metrics, with the METRICS_USERNAME and METRICS_PASSWORD basic auth or from one METRICS_ALLOWED_IPS address
+func isMetricsRequestAllowed(app App, c echo.Context) bool {
+	cfg := app.GetConfiguration()
+
+	if password := cfg.GetF("METRICS_PASSWORD", ""); password != "" {
+		username, received, ok := c.Request().BasicAuth()
+		if ok &&
+			subtle.ConstantTimeCompare([]byte(username), []byte(cfg.GetF("METRICS_USERNAME", "metrics"))) == 1 &&
+			subtle.ConstantTimeCompare([]byte(received), []byte(password)) == 1 {
+			return true
+		}
+	}
+
+	// the connection address, X-Forwarded-For can be set by the client
+	host, _, err := net.SplitHostPort(c.Request().RemoteAddr)
+	if err != nil {
+		host = c.Request().RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, allowed := range splitConfigurationList(cfg.GetF("METRICS_ALLOWED_IPS", "127.0.0.1,::1")) {
+		if _, network, err := net.ParseCIDR(allowed); err == nil {
+			if network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+
+		if allowedIP := net.ParseIP(allowed); allowedIP != nil && allowedIP.Equal(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Handler that writes the app metrics in the Prometheus text format
+func metricsHandler(app App) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if !isMetricsRequestAllowed(app, c) {
+			return &HTTPError{
+				Code:    http.StatusForbidden,
+				Message: "Forbidden",
+			}
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, metrics.ContentType)
+		c.Response().WriteHeader(http.StatusOK)
+
+		return app.GetMetrics().WriteText(c.Response())
+	}
+}
@@ -0,0 +1,398 @@
+// Package metrics implements counters, gauges and histograms exposed in the Prometheus text format
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Content type of the text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Default histogram buckets in seconds
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type metric interface {
+	write(w *bufio.Writer)
+}
+
+// Registry - Metrics registered in one app
+type Registry struct {
+	mu        sync.Mutex
+	metrics   map[string]metric
+	names     []string
+	onCollect []func()
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		metrics: map[string]metric{},
+	}
+}
+
+func (r *Registry) register(name string, m metric) metric {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if registered, ok := r.metrics[name]; ok {
+		return registered
+	}
+
+	r.metrics[name] = m
+	r.names = append(r.names, name)
+	sort.Strings(r.names)
+
+	return m
+}
+
+// NewCounter - Register one counter, returns the registered counter if the name is already in use
+func (r *Registry) NewCounter(name, help string, labelNames ...string) *Counter {
+	c := &Counter{vec: newVec(name, help, "counter", labelNames)}
+
+	registered, ok := r.register(name, c).(*Counter)
+	if !ok {
+		panic("metrics: " + name + " is already registered with other type")
+	}
+
+	return registered
+}
+
+// NewGauge - Register one gauge, returns the registered gauge if the name is already in use
+func (r *Registry) NewGauge(name, help string, labelNames ...string) *Gauge {
+	g := &Gauge{vec: newVec(name, help, "gauge", labelNames)}
+
+	registered, ok := r.register(name, g).(*Gauge)
+	if !ok {
+		panic("metrics: " + name + " is already registered with other type")
+	}
+
+	return registered
+}
+
+// NewHistogram - Register one histogram, returns the registered histogram if the name is already in use
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labelNames ...string) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+
+	h := &Histogram{
+		vec:     newVec(name, help, "histogram", labelNames),
+		buckets: append([]float64{}, buckets...),
+		series:  map[string]*histogramSeries{},
+	}
+	sort.Float64s(h.buckets)
+
+	registered, ok := r.register(name, h).(*Histogram)
+	if !ok {
+		panic("metrics: " + name + " is already registered with other type")
+	}
+
+	return registered
+}
+
+// OnCollect - Run one function before each write, used to update gauges from external sources
+func (r *Registry) OnCollect(f func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.onCollect = append(r.onCollect, f)
+}
+
+// WriteText - Write all metrics in the Prometheus text format
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	onCollect := append([]func(){}, r.onCollect...)
+	r.mu.Unlock()
+
+	for _, f := range onCollect {
+		f()
+	}
+
+	r.mu.Lock()
+	names := append([]string{}, r.names...)
+	metrics := make([]metric, 0, len(names))
+	for _, name := range names {
+		metrics = append(metrics, r.metrics[name])
+	}
+	r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, m := range metrics {
+		m.write(bw)
+	}
+
+	return bw.Flush()
+}
+
+// vec - Series of one metric by label values
+type vec struct {
+	mu         sync.Mutex
+	name       string
+	help       string
+	kind       string
+	labelNames []string
+	values     map[string]float64
+}
+
+func newVec(name, help, kind string, labelNames []string) vec {
+	return vec{
+		name:       name,
+		help:       help,
+		kind:       kind,
+		labelNames: labelNames,
+		values:     map[string]float64{},
+	}
+}
+
+func (v *vec) key(labelValues []string) string {
+	if len(labelValues) != len(v.labelNames) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", v.name, len(v.labelNames), len(labelValues)))
+	}
+
+	return strings.Join(labelValues, "\xff")
+}
+
+func (v *vec) writeHeader(w *bufio.Writer) {
+	w.WriteString("# HELP " + v.name + " " + escapeHelp(v.help) + "\n")
+	w.WriteString("# TYPE " + v.name + " " + v.kind + "\n")
+}
+
+func (v *vec) writeValues(w *bufio.Writer) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.writeHeader(w)
+
+	keys := make([]string, 0, len(v.values))
+	for k := range v.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		w.WriteString(v.name + formatLabels(v.labelNames, splitKey(k, len(v.labelNames))) + " " + formatValue(v.values[k]) + "\n")
+	}
+}
+
+// Counter - Value that only increases
+type Counter struct {
+	vec
+}
+
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *Counter) Add(value float64, labelValues ...string) {
+	if value < 0 {
+		panic("metrics: " + c.name + " counter can't decrease")
+	}
+
+	k := c.key(labelValues)
+
+	c.mu.Lock()
+	c.values[k] += value
+	c.mu.Unlock()
+}
+
+// SetTotal - Set the counter from one external total that only increases, ex: database/sql stats.
+// The total of one new external source can start lower, Prometheus handles it as one counter reset
+func (c *Counter) SetTotal(value float64, labelValues ...string) {
+	if value < 0 {
+		panic("metrics: " + c.name + " counter can't be negative")
+	}
+
+	k := c.key(labelValues)
+
+	c.mu.Lock()
+	c.values[k] = value
+	c.mu.Unlock()
+}
+
+// Get - Current counter value, mostly used in tests
+func (c *Counter) Get(labelValues ...string) float64 {
+	k := c.key(labelValues)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.values[k]
+}
+
+func (c *Counter) write(w *bufio.Writer) {
+	c.writeValues(w)
+}
+
+// Gauge - Value that can go up and down
+type Gauge struct {
+	vec
+}
+
+func (g *Gauge) Set(value float64, labelValues ...string) {
+	k := g.key(labelValues)
+
+	g.mu.Lock()
+	g.values[k] = value
+	g.mu.Unlock()
+}
+
+func (g *Gauge) Add(value float64, labelValues ...string) {
+	k := g.key(labelValues)
+
+	g.mu.Lock()
+	g.values[k] += value
+	g.mu.Unlock()
+}
+
+func (g *Gauge) Get(labelValues ...string) float64 {
+	k := g.key(labelValues)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.values[k]
+}
+
+// Reset - Remove all series, used before setting gauges from one external source
+func (g *Gauge) Reset() {
+	g.mu.Lock()
+	g.values = map[string]float64{}
+	g.mu.Unlock()
+}
+
+func (g *Gauge) write(w *bufio.Writer) {
+	g.writeValues(w)
+}
+
+// Histogram - Observations counted in cumulative buckets
+type Histogram struct {
+	vec
+	buckets []float64
+	series  map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	k := h.key(labelValues)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := h.series[k]
+	if s == nil {
+		s = &histogramSeries{counts: make([]uint64, len(h.buckets))}
+		h.series[k] = s
+	}
+
+	for i, upper := range h.buckets {
+		if value <= upper {
+			s.counts[i]++
+		}
+	}
+	s.count++
+	s.sum += value
+}
+
+// Count - Number of observations, mostly used in tests
+func (h *Histogram) Count(labelValues ...string) uint64 {
+	k := h.key(labelValues)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if s := h.series[k]; s != nil {
+		return s.count
+	}
+
+	return 0
+}
+
+func (h *Histogram) write(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.writeHeader(w)
+
+	keys := make([]string, 0, len(h.series))
+	for k := range h.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	labelNames := append(append([]string{}, h.labelNames...), "le")
+
+	for _, k := range keys {
+		s := h.series[k]
+		labelValues := splitKey(k, len(h.labelNames))
+
+		for i, upper := range h.buckets {
+			w.WriteString(h.name + "_bucket" + formatLabels(labelNames, append(labelValues, formatValue(upper))) + " " + strconv.FormatUint(s.counts[i], 10) + "\n")
+		}
+		w.WriteString(h.name + "_bucket" + formatLabels(labelNames, append(labelValues, "+Inf")) + " " + strconv.FormatUint(s.count, 10) + "\n")
+
+		labels := formatLabels(h.labelNames, labelValues)
+		w.WriteString(h.name + "_sum" + labels + " " + formatValue(s.sum) + "\n")
+		w.WriteString(h.name + "_count" + labels + " " + strconv.FormatUint(s.count, 10) + "\n")
+	}
+}
+
+func splitKey(key string, size int) []string {
+	if size == 0 {
+		return []string{}
+	}
+
+	return strings.Split(key, "\xff")
+}
+
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("{")
+	for i, name := range names {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(name + `="` + escapeLabelValue(values[i]) + `"`)
+	}
+	b.WriteString("}")
+
+	return b.String()
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+var helpReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+func escapeLabelValue(v string) string {
+	return labelValueReplacer.Replace(v)
+}
+
+func escapeHelp(v string) string {
+	return helpReplacer.Replace(v)
+}
@@ -0,0 +1,73 @@
+package metrics_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-bolo/bolo/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_WriteText(t *testing.T) {
+	r := metrics.NewRegistry()
+
+	c := r.NewCounter("app_requests_total", "Requests.", "route")
+	c.Inc("/users/:id")
+	c.Add(2, `/say/"hi"`)
+	assert.Same(t, c, r.NewCounter("app_requests_total", "Requests.", "route"))
+
+	g := r.NewGauge("app_up", "Up.")
+	r.OnCollect(func() {
+		g.Set(1)
+	})
+
+	h := r.NewHistogram("app_duration_seconds", "Durations.", []float64{0.1, 1})
+	h.Observe(0.05)
+	h.Observe(0.5)
+
+	buf := bytes.Buffer{}
+	err := r.WriteText(&buf)
+	assert.Nil(t, err)
+
+	expected := `# HELP app_duration_seconds Durations.
+# TYPE app_duration_seconds histogram
+app_duration_seconds_bucket{le="0.1"} 1
+app_duration_seconds_bucket{le="1"} 2
+app_duration_seconds_bucket{le="+Inf"} 2
+app_duration_seconds_sum 0.55
+app_duration_seconds_count 2
+# HELP app_requests_total Requests.
+# TYPE app_requests_total counter
+app_requests_total{route="/say/\"hi\""} 2
+app_requests_total{route="/users/:id"} 1
+# HELP app_up Up.
+# TYPE app_up gauge
+app_up 1
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestRegistry_NewCounter(t *testing.T) {
+	r := metrics.NewRegistry()
+	r.NewGauge("app_value", "Value.")
+
+	assert.Panics(t, func() {
+		r.NewCounter("app_value", "Value.")
+	})
+	assert.Panics(t, func() {
+		r.NewCounter("app_total", "Total.", "route").Inc()
+	})
+}
+
+func TestCounter_SetTotal(t *testing.T) {
+	r := metrics.NewRegistry()
+	c := r.NewCounter("app_waits_total", "Waits.", "db")
+
+	c.SetTotal(3, "default")
+	c.SetTotal(5, "default")
+	assert.Equal(t, float64(5), c.Get("default"))
+
+	assert.Panics(t, func() {
+		c.SetTotal(-1, "default")
+	})
+}
@@ -0,0 +1,71 @@
+package bolo_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	os.Setenv("METRICS_ENABLED", "true")
+	os.Setenv("METRICS_PASSWORD", "metrics-secret")
+	defer os.Unsetenv("METRICS_ENABLED")
+	defer os.Unsetenv("METRICS_PASSWORD")
+
+	app := GetTestApp()
+	err := app.Bootstrap()
+	assert.Nil(t, err)
+
+	app.GetRouter().GET("/users/:id", func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+
+	for _, url := range []string{"/users/1", "/users/2", "/unknown"} {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		req.Header.Set(echo.HeaderAccept, "application/json")
+		app.GetRouter().ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	scrape := func(remoteAddr, password string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.RemoteAddr = remoteAddr
+		if password != "" {
+			req.SetBasicAuth("metrics", password)
+		}
+		rec := httptest.NewRecorder()
+		app.GetRouter().ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("should block other addresses without password", func(t *testing.T) {
+		rec := scrape("203.0.113.10:4000", "")
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+
+		rec = scrape("203.0.113.10:4000", "invalid")
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("should allow the configured password", func(t *testing.T) {
+		rec := scrape("203.0.113.10:4000", "metrics-secret")
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("should write metrics to local addresses", func(t *testing.T) {
+		rec := scrape("127.0.0.1:4000", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, strings.HasPrefix(rec.Header().Get(echo.HeaderContentType), "text/plain; version=0.0.4"))
+
+		body := rec.Body.String()
+		assert.Contains(t, body, `bolo_http_requests_total{method="GET",route="/users/:id",status="200"} 2`)
+		assert.Contains(t, body, `bolo_http_requests_total{method="GET",route="notFound",status="404"} 1`)
+		assert.Contains(t, body, `bolo_http_request_duration_seconds_count{method="GET",route="/users/:id"} 2`)
+		assert.Contains(t, body, `bolo_http_errors_total{status="403"} 2`)
+		assert.Contains(t, body, `bolo_http_errors_total{status="404"} 1`)
+		assert.Contains(t, body, `bolo_events_total{event="bootstrap"} 1`)
+	})
+}
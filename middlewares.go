@@ -20,14 +20,9 @@ func BindMiddlewares(app App, p *Plugin) {
 		RedirectCode: http.StatusMovedPermanently,
 	}))
 
+	router.Use(requestIDMiddleware(app))
 	router.Use(middleware.Gzip())
-	router.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowCredentials: app.GetConfiguration().GetBoolF("CORS_ALLOW_CREDENTIALS", true),
-		MaxAge:           app.GetConfiguration().GetIntF("CORS_MAX_AGE", 18000), // seccounds
-		AllowOriginFunc: func(origin string) (bool, error) {
-			return true, nil
-		},
-	}))
+	router.Use(corsMiddleware(app))
 
 	router.Use(acceptResolverMiddleware(app))
 
@@ -35,6 +30,32 @@ func BindMiddlewares(app App, p *Plugin) {
 
 	router.Use(initAppCtx(app))
 
+	router.Use(tracingMiddleware(app))
+
+	if app.GetConfiguration().GetBoolF("METRICS_ENABLED", false) {
+		router.Use(metricsMiddleware(app))
+	}
+
+	if app.GetConfiguration().GetBoolF("ACCESS_LOG_ENABLED", true) {
+		router.Use(accessLogMiddleware(app))
+	}
+
+	if app.GetConfiguration().GetBoolF("SECURITY_HEADERS_ENABLED", true) {
+		router.Use(securityHeadersMiddleware(app))
+	}
+
+	router.Use(sessionMiddleware(app))
+	router.Use(authenticationRateLimitMiddleware(app))
+	router.Use(authenticationMiddleware(app))
+	router.Use(rateLimitMiddleware(app))
+	router.Use(flashMessagesMiddleware(app))
+
+	if app.GetConfiguration().GetBoolF("CSRF_ENABLED", true) {
+		router.Use(csrfMiddleware(app))
+	}
+
+	router.Use(dbContextMiddleware(app))
+
 	if goEnv == "development" {
 		router.Debug = true
 	}
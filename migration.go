@@ -23,7 +23,33 @@ func (m *MigrationEngine) SetupMigrationEngine() error {
 	app := m.App
 	db := app.GetDB()
 
-	err := db.Exec(`CREATE TABLE IF NOT EXISTS bolo_migrations (
+	var ddl string
+
+	switch db.Dialector.Name() {
+	case "postgres":
+		ddl = `CREATE TABLE IF NOT EXISTS bolo_migrations (
+		plugin_name varchar(200) NOT NULL,
+		version INT NULL,
+		last_upgrade_name varchar(255) NULL,
+		installed boolean DEFAULT false NOT NULL,
+		created_at timestamptz DEFAULT NOW() NOT NULL,
+		updated_at timestamptz DEFAULT NOW() NOT NULL,
+		last_error TEXT NULL,
+		CONSTRAINT bolo_migrations_pkey PRIMARY KEY (plugin_name)
+	)`
+	case "sqlite":
+		ddl = `CREATE TABLE IF NOT EXISTS bolo_migrations (
+		plugin_name varchar(200) NOT NULL,
+		version INT NULL,
+		last_upgrade_name varchar(255) NULL,
+		installed bool DEFAULT false NOT NULL,
+		created_at datetime DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		updated_at datetime DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		last_error TEXT NULL,
+		CONSTRAINT plugin_name PRIMARY KEY (plugin_name)
+	)`
+	default:
+		ddl = `CREATE TABLE IF NOT EXISTS bolo_migrations (
 		plugin_name varchar(200) NOT NULL,
 		version INT NULL,
 		last_upgrade_name varchar(255) NULL,
@@ -32,7 +58,10 @@ func (m *MigrationEngine) SetupMigrationEngine() error {
 		updated_at datetime DEFAULT NOW() NOT NULL,
 		last_error TEXT NULL,
 		CONSTRAINT plugin_name PRIMARY KEY (plugin_name)
-	)`).Error
+	)`
+	}
+
+	err := db.Exec(ddl).Error
 	if err != nil {
 		return err
 	}
@@ -66,6 +95,57 @@ func (m *MigrationEngine) FindAllMigrationsByPlugin() (map[string]*MigrationMode
 	return d, nil
 }
 
+// MigrationStatus - Migrations ran and pending of one plugin
+type MigrationStatus struct {
+	PluginName string `json:"pluginName"`
+	Version    int    `json:"version"`
+	Total      int    `json:"total"`
+	Pending    int    `json:"pending"`
+	LastError  string `json:"lastError,omitempty"`
+}
+
+// GetStatus - Compare the saved migration versions with the migrations of each plugin
+func (m *MigrationEngine) GetStatus() ([]*MigrationStatus, error) {
+	saved := map[string]*MigrationModel{}
+
+	// migrations never ran
+	if m.App.GetDB().Migrator().HasTable(&MigrationModel{}) {
+		var err error
+		saved, err = m.FindAllMigrationsByPlugin()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	list := []*MigrationStatus{}
+
+	for _, p := range m.App.GetPlugins() {
+		migs := p.GetMigrations()
+		if len(migs) == 0 {
+			continue
+		}
+
+		status := MigrationStatus{
+			PluginName: p.GetName(),
+			Total:      len(migs),
+		}
+
+		if s := saved[p.GetName()]; s != nil {
+			status.Version = s.Version
+			status.LastError = s.LastError
+		}
+
+		status.Pending = status.Total - status.Version
+		if status.Pending < 0 {
+			status.Pending = 0
+		}
+
+		list = append(list, &status)
+	}
+
+	return list, nil
+}
+
 func (m *MigrationEngine) GetPluginMigrations() ([]*Migration, error) {
 	return []*Migration{}, nil
 }
@@ -0,0 +1,27 @@
+package bolo_test
+
+import (
+	"testing"
+
+	"github.com/go-bolo/bolo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationEngine_Up(t *testing.T) {
+	app := GetTestApp()
+	err := app.Bootstrap()
+	assert.Nil(t, err)
+
+	err = bolo.Up(app)
+	assert.Nil(t, err)
+
+	m := bolo.NewMigrationEngine(&bolo.NewMigrationEngineOpts{App: app})
+	list, err := m.GetStatus()
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(list)) {
+		assert.Equal(t, "bolo", list[0].PluginName)
+		assert.Equal(t, 0, list[0].Pending)
+	}
+
+	assert.True(t, app.GetDB().Migrator().HasTable(&bolo.SessionModel{}))
+}
@@ -1,6 +1,7 @@
 package bolo_test
 
 import (
+	"errors"
 	"strconv"
 	"time"
 
@@ -64,3 +65,52 @@ func FindOneURL(c *bolo.RequestContext, id string) (*URLModel, error) {
 	err := db.First(&record, id).Error
 	return &record, err
 }
+
+type UserModel struct {
+	ID          string
+	Roles       []string
+	Email       string
+	Username    string
+	DisplayName string
+	FullName    string
+	Language    string
+	Active      bool
+	Blocked     bool
+}
+
+func (r *UserModel) GetID() string                 { return r.ID }
+func (r *UserModel) SetID(id string) error         { r.ID = id; return nil }
+func (r *UserModel) GetRoles() []string            { return r.Roles }
+func (r *UserModel) SetRoles(v []string) error     { r.Roles = v; return nil }
+func (r *UserModel) AddRole(role string) error     { r.Roles = append(r.Roles, role); return nil }
+func (r *UserModel) RemoveRole(role string) error  { return nil }
+func (r *UserModel) GetEmail() string              { return r.Email }
+func (r *UserModel) SetEmail(v string) error       { r.Email = v; return nil }
+func (r *UserModel) GetUsername() string           { return r.Username }
+func (r *UserModel) SetUsername(v string) error    { r.Username = v; return nil }
+func (r *UserModel) GetDisplayName() string        { return r.DisplayName }
+func (r *UserModel) SetDisplayName(v string) error { r.DisplayName = v; return nil }
+func (r *UserModel) GetFullName() string           { return r.FullName }
+func (r *UserModel) SetFullName(v string) error    { r.FullName = v; return nil }
+func (r *UserModel) GetLanguage() string           { return r.Language }
+func (r *UserModel) SetLanguage(v string) error    { r.Language = v; return nil }
+func (r *UserModel) IsActive() bool                { return r.Active }
+func (r *UserModel) SetActive(v bool) error        { r.Active = v; return nil }
+func (r *UserModel) IsBlocked() bool               { return r.Blocked }
+func (r *UserModel) SetBlocked(v bool) error       { r.Blocked = v; return nil }
+
+// FillById - mocked users with id "blocked" are blocked, "missing" doesn't exist and "error" returns a load error
+func (r *UserModel) FillById(ID string) error {
+	switch ID {
+	case "missing":
+		return bolo.ErrUserNotFound
+	case "error":
+		return errors.New("database unavailable")
+	}
+
+	r.ID = ID
+	r.Active = true
+	r.Blocked = ID == "blocked"
+	r.Roles = []string{"editor"}
+	return nil
+}
@@ -0,0 +1,256 @@
+package bolo
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Permission to read the registered model schemas in /api/models
+const PermissionViewModelSchema = "view_model_schema"
+
+// ModelSchema - Metadata of one model registered with App.SetModel
+type ModelSchema struct {
+	Name       string                 `json:"name"`
+	Type       string                 `json:"type"`
+	Table      string                 `json:"table"`
+	PrimaryKey string                 `json:"primaryKey,omitempty"`
+	Fields     []*ModelSchemaField    `json:"fields"`
+	Relations  []*ModelSchemaRelation `json:"relations"`
+
+	model interface{}
+}
+
+type ModelSchemaField struct {
+	Name string `json:"name"`
+	// Empty for fields not encoded in JSON, with the json:"-" tag
+	JSONName   string `json:"jsonName,omitempty"`
+	Column     string `json:"column,omitempty"`
+	Type       string `json:"type"`
+	DataType   string `json:"dataType,omitempty"`
+	PrimaryKey bool   `json:"primaryKey,omitempty"`
+	NotNull    bool   `json:"notNull,omitempty"`
+	Unique     bool   `json:"unique,omitempty"`
+	Size       int    `json:"size,omitempty"`
+	// validate tag, ex: required,email
+	Validate string `json:"validate,omitempty"`
+	Required bool   `json:"required,omitempty"`
+	// Query param and type of the fields with the query parser filter tag, ex: filter:"param:title;type:string"
+	FilterParam string `json:"filterParam,omitempty"`
+	FilterType  string `json:"filterType,omitempty"`
+}
+
+type ModelSchemaRelation struct {
+	Name     string `json:"name"`
+	JSONName string `json:"jsonName,omitempty"`
+	// has_one, has_many, belongs_to or many_to_many
+	Kind string `json:"kind"`
+	// Go type and table of the related model
+	Type        string   `json:"type"`
+	Table       string   `json:"table"`
+	ForeignKeys []string `json:"foreignKeys,omitempty"`
+	JoinTable   string   `json:"joinTable,omitempty"`
+}
+
+// GetModel - Get the model registered with the schema
+func (s *ModelSchema) GetModel() interface{} {
+	return s.model
+}
+
+// LookUpField - Find one field by the struct field name, JSON name or column, ex: to check filter params
+func (s *ModelSchema) LookUpField(name string) *ModelSchemaField {
+	for _, field := range s.Fields {
+		if field.Name == name || field.JSONName == name || field.Column == name {
+			return field
+		}
+	}
+
+	return nil
+}
+
+// NewModelSchema - Parse the model fields, gorm columns, JSON names, validation tags and relations
+func NewModelSchema(name string, model interface{}, namer schema.Namer) (*ModelSchema, error) {
+	if namer == nil {
+		namer = schema.NamingStrategy{}
+	}
+
+	s, err := schema.Parse(model, &sync.Map{}, namer)
+	if err != nil {
+		return nil, err
+	}
+
+	ms := ModelSchema{
+		Name:      name,
+		Type:      s.ModelType.String(),
+		Table:     s.Table,
+		Fields:    []*ModelSchemaField{},
+		Relations: []*ModelSchemaRelation{},
+		model:     model,
+	}
+
+	if s.PrioritizedPrimaryField != nil {
+		ms.PrimaryKey = s.PrioritizedPrimaryField.DBName
+	}
+
+	for _, field := range s.Fields {
+		if _, isRelation := s.Relationships.Relations[field.Name]; isRelation {
+			continue
+		}
+
+		validate := field.Tag.Get("validate")
+
+		f := ModelSchemaField{
+			Name:       field.Name,
+			JSONName:   getJSONName(field.StructField),
+			Column:     field.DBName,
+			Type:       field.FieldType.String(),
+			DataType:   string(field.DataType),
+			PrimaryKey: field.PrimaryKey,
+			NotNull:    field.NotNull,
+			Unique:     field.Unique,
+			Size:       field.Size,
+			Validate:   validate,
+			Required:   hasValidateRule(validate, "required"),
+		}
+
+		// the query parser only reads the filters of the model struct fields, not embedded ones
+		if filter, ok := field.Tag.Lookup("filter"); ok && filter != "-" && len(field.BindNames) == 1 {
+			f.FilterParam, f.FilterType = parseFilterTag(field.Name, filter)
+		}
+
+		ms.Fields = append(ms.Fields, &f)
+	}
+
+	relationNames := []string{}
+	for relationName := range s.Relationships.Relations {
+		relationNames = append(relationNames, relationName)
+	}
+	sort.Strings(relationNames)
+
+	for _, relationName := range relationNames {
+		relation := s.Relationships.Relations[relationName]
+
+		r := ModelSchemaRelation{
+			Name:     relation.Name,
+			JSONName: getJSONName(relation.Field.StructField),
+			Kind:     string(relation.Type),
+			Type:     relation.FieldSchema.ModelType.String(),
+			Table:    relation.FieldSchema.Table,
+		}
+
+		for _, ref := range relation.References {
+			if ref.OwnPrimaryKey && relation.JoinTable == nil {
+				continue
+			}
+			r.ForeignKeys = append(r.ForeignKeys, ref.ForeignKey.DBName)
+		}
+
+		if relation.JoinTable != nil {
+			r.JoinTable = relation.JoinTable.Table
+		}
+
+		ms.Relations = append(ms.Relations, &r)
+	}
+
+	return &ms, nil
+}
+
+// Get the JSON object key of one struct field, empty if the field is not encoded
+func getJSONName(field reflect.StructField) string {
+	tag, ok := field.Tag.Lookup("json")
+	if !ok {
+		return field.Name
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	default:
+		return name
+	}
+}
+
+// Parse one query parser filter tag, with the default param as the field name and type as default
+func parseFilterTag(fieldName, tag string) (string, string) {
+	param, filterType := fieldName, "default"
+
+	for _, option := range strings.Split(tag, ";") {
+		key, value, _ := strings.Cut(option, ":")
+		switch {
+		case key == "param" && value != "":
+			param = value
+		case key == "type" && value != "":
+			filterType = value
+		}
+	}
+
+	return param, filterType
+}
+
+func hasValidateRule(validate, rule string) bool {
+	for _, r := range strings.Split(validate, ",") {
+		if name, _, _ := strings.Cut(r, "="); name == rule {
+			return true
+		}
+	}
+
+	return false
+}
+
+type ModelSchemaListResponse struct {
+	BaseListReponse
+	Records []*ModelSchema `json:"records"`
+}
+
+type ModelSchemaResponse struct {
+	Record *ModelSchema `json:"record"`
+}
+
+// Handler of /api/models with the schemas of all registered models
+func modelSchemasHandler(app App) Action {
+	return func(c echo.Context) error {
+		list := app.GetModelSchemas()
+
+		resp := ModelSchemaListResponse{Records: list}
+		resp.Meta.Count = int64(len(list))
+
+		return c.JSON(http.StatusOK, &resp)
+	}
+}
+
+// Handler of /api/models/:name with the schema of one registered model
+func modelSchemaHandler(app App) Action {
+	return func(c echo.Context) error {
+		s := app.GetModelSchema(c.Param("name"))
+		if s == nil {
+			return &HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Not Found",
+			}
+		}
+
+		return c.JSON(http.StatusOK, &ModelSchemaResponse{Record: s})
+	}
+}
+
+// Get the naming strategy of the gorm options, used to parse the model schemas before the database connection
+func getModelSchemaNamer(app *AppStruct) schema.Namer {
+	if app.DB != nil {
+		return app.DB.NamingStrategy
+	}
+
+	if o, ok := app.Options.GormOptions.(*gorm.Config); ok && o.NamingStrategy != nil {
+		return o.NamingStrategy
+	}
+
+	return schema.NamingStrategy{}
+}
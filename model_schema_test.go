@@ -0,0 +1,142 @@
+package bolo_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-bolo/bolo"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type schemaTestAuthor struct {
+	ID    uint64 `gorm:"primaryKey" json:"id"`
+	Email string `gorm:"size:255;unique;not null" json:"email" validate:"required,email"`
+}
+
+type schemaTestTag struct {
+	ID   uint64 `gorm:"primaryKey" json:"id"`
+	Name string `json:"name"`
+}
+
+type schemaTestArticle struct {
+	ID       uint64            `gorm:"primaryKey" json:"id"`
+	Title    string            `gorm:"column:title;size:100" json:"title" validate:"required,max=100"`
+	Secret   string            `json:"-"`
+	Computed string            `gorm:"-" json:"computed"`
+	AuthorID uint64            `json:"authorId"`
+	Author   *schemaTestAuthor `json:"author"`
+	Tags     []*schemaTestTag  `gorm:"many2many:article_tags" json:"tags"`
+}
+
+func TestNewModelSchema(t *testing.T) {
+	s, err := bolo.NewModelSchema("article", &schemaTestArticle{}, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "article", s.Name)
+	assert.Equal(t, "bolo_test.schemaTestArticle", s.Type)
+	assert.Equal(t, "schema_test_articles", s.Table)
+	assert.Equal(t, "id", s.PrimaryKey)
+
+	t.Run("should parse the fields", func(t *testing.T) {
+		tests := []struct {
+			name string
+			want bolo.ModelSchemaField
+		}{
+			{
+				name: "Title",
+				want: bolo.ModelSchemaField{Name: "Title", JSONName: "title", Column: "title", Type: "string", DataType: "string", Size: 100, Validate: "required,max=100", Required: true},
+			},
+			{
+				name: "Secret",
+				want: bolo.ModelSchemaField{Name: "Secret", Column: "secret", Type: "string", DataType: "string"},
+			},
+			{
+				name: "Computed",
+				want: bolo.ModelSchemaField{Name: "Computed", JSONName: "computed", Type: "string"},
+			},
+			{
+				name: "authorId",
+				want: bolo.ModelSchemaField{Name: "AuthorID", JSONName: "authorId", Column: "author_id", Type: "uint64", DataType: "uint", Size: 64},
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				field := s.LookUpField(tt.name)
+				if assert.NotNil(t, field) {
+					assert.Equal(t, tt.want, *field)
+				}
+			})
+		}
+
+		assert.Nil(t, s.LookUpField("author"))
+		assert.Nil(t, s.LookUpField("unknown"))
+	})
+
+	t.Run("should parse the relations", func(t *testing.T) {
+		assert.Equal(t, []*bolo.ModelSchemaRelation{
+			{Name: "Author", JSONName: "author", Kind: "belongs_to", Type: "bolo_test.schemaTestAuthor", Table: "schema_test_authors", ForeignKeys: []string{"author_id"}},
+			{Name: "Tags", JSONName: "tags", Kind: "many_to_many", Type: "bolo_test.schemaTestTag", Table: "schema_test_tags", ForeignKeys: []string{"schema_test_article_id", "schema_test_tag_id"}, JoinTable: "article_tags"},
+		}, s.Relations)
+	})
+
+	t.Run("should return error for models that are not structs", func(t *testing.T) {
+		_, err := bolo.NewModelSchema("func", func() {}, nil)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestModelSchemaHandler(t *testing.T) {
+	app := GetTestApp()
+	assert.Nil(t, app.Bootstrap())
+	app.SetModel("article", &schemaTestArticle{})
+	app.SetModel("author", &schemaTestAuthor{})
+	app.SetModel("factory", func() {})
+
+	assert.Nil(t, app.GetModelSchema("factory"))
+	assert.Equal(t, "article", app.GetModelName(&schemaTestArticle{}))
+
+	serve := func(url string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		req.Header.Set(echo.HeaderAccept, "application/json")
+		rec := httptest.NewRecorder()
+
+		app.GetRouter().ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("should return 403 without the view_model_schema permission", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, serve("/api/models").Code)
+		assert.Equal(t, http.StatusForbidden, serve("/api/models/article").Code)
+	})
+
+	app.SetRolePermission("unAuthenticated", bolo.PermissionViewModelSchema, true)
+	defer app.SetRolePermission("unAuthenticated", bolo.PermissionViewModelSchema, false)
+
+	t.Run("should list the model schemas", func(t *testing.T) {
+		rec := serve("/api/models")
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		resp := bolo.ModelSchemaListResponse{}
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, int64(2), resp.Meta.Count)
+		if assert.Len(t, resp.Records, 2) {
+			assert.Equal(t, "article", resp.Records[0].Name)
+			assert.Equal(t, "author", resp.Records[1].Name)
+		}
+	})
+
+	t.Run("should return one model schema", func(t *testing.T) {
+		rec := serve("/api/models/author")
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		resp := bolo.ModelSchemaResponse{}
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "schema_test_authors", resp.Record.Table)
+		assert.True(t, resp.Record.LookUpField("email").Required)
+
+		assert.Equal(t, http.StatusNotFound, serve("/api/models/factory").Code)
+	})
+}
@@ -0,0 +1,14 @@
+package models
+
+// Auditable - Models with the create, update and delete changes stored in the bolo_audit_log table
+type Auditable interface {
+	IsAuditable() bool
+}
+
+// Audited - Embed in one model to enable the audit log.
+// Fields with the audit:"-" tag, ex: passwords, are not stored in the log
+type Audited struct{}
+
+func (Audited) IsAuditable() bool {
+	return true
+}
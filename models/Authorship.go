@@ -0,0 +1,27 @@
+package models
+
+import "context"
+
+// Authorship - Embed in one model to store the users that created and last updated the record.
+// Filled from the author in the query context, RequestContext.DB() sets the authenticated user
+type Authorship struct {
+	CreatedBy string `gorm:"column:created_by;size:255" json:"createdBy,omitempty"`
+	UpdatedBy string `gorm:"column:updated_by;size:255" json:"updatedBy,omitempty"`
+}
+
+type authorContextKey struct{}
+
+// ContextWithAuthor - Set the user id used in the CreatedBy and UpdatedBy columns
+func ContextWithAuthor(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, authorContextKey{}, userID)
+}
+
+// AuthorFromContext - Get the user id set with ContextWithAuthor, empty if not set
+func AuthorFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	userID, _ := ctx.Value(authorContextKey{}).(string)
+	return userID
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const versionLockedKey = "models:versionLocked"
+
+// RegisterCallbacks - Register the gorm callbacks of the Authorship and Versioned columns in one database
+func RegisterCallbacks(db *gorm.DB) error {
+	cb := db.Callback()
+
+	if err := cb.Create().Before("gorm:create").Register("models:before_create", beforeCreate); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("models:before_update", beforeUpdate); err != nil {
+		return err
+	}
+
+	return cb.Update().After("gorm:update").Register("models:after_update", afterUpdate)
+}
+
+func beforeCreate(tx *gorm.DB) {
+	if tx.Error != nil || tx.Statement.Schema == nil {
+		return
+	}
+
+	if author := AuthorFromContext(tx.Statement.Context); author != "" {
+		if tx.Statement.Schema.LookUpField("CreatedBy") != nil {
+			tx.Statement.SetColumn("CreatedBy", author, true)
+		}
+		if tx.Statement.Schema.LookUpField("UpdatedBy") != nil {
+			tx.Statement.SetColumn("UpdatedBy", author, true)
+		}
+	}
+
+	if f := tx.Statement.Schema.LookUpField("Version"); f != nil {
+		setInitialVersion(tx, f)
+	}
+}
+
+// Set version 1 in new records, in one record or in one slice
+func setInitialVersion(tx *gorm.DB, f *schema.Field) {
+	rv := tx.Statement.ReflectValue
+
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			item := reflect.Indirect(rv.Index(i))
+			if _, isZero := f.ValueOf(tx.Statement.Context, item); isZero {
+				f.Set(tx.Statement.Context, item, uint64(1))
+			}
+		}
+	case reflect.Struct:
+		if _, isZero := f.ValueOf(tx.Statement.Context, rv); isZero {
+			f.Set(tx.Statement.Context, rv, uint64(1))
+		}
+	}
+}
+
+func beforeUpdate(tx *gorm.DB) {
+	if tx.Error != nil || tx.Statement.Schema == nil {
+		return
+	}
+
+	if author := AuthorFromContext(tx.Statement.Context); author != "" && tx.Statement.Schema.LookUpField("UpdatedBy") != nil {
+		tx.Statement.SetColumn("UpdatedBy", author, true)
+	}
+
+	// the creator never changes, ex: db.Save() with one record without the loaded CreatedBy
+	if field := tx.Statement.Schema.LookUpField("CreatedBy"); field != nil {
+		tx.Statement.Omits = append(tx.Statement.Omits, field.DBName)
+	}
+
+	field := tx.Statement.Schema.LookUpField("Version")
+	if field == nil || tx.Statement.ReflectValue.Kind() != reflect.Struct {
+		return
+	}
+
+	// updates without the loaded version, ex: db.Model(&Model{}).Where(...).Update(...), don't use the lock
+	v, isZero := field.ValueOf(tx.Statement.Context, tx.Statement.ReflectValue)
+	if isZero {
+		return
+	}
+	version, ok := v.(uint64)
+	if !ok {
+		return
+	}
+
+	tx.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: version},
+	}})
+	tx.Statement.SetColumn("Version", version+1, true)
+	tx.InstanceSet(versionLockedKey, version)
+}
+
+func afterUpdate(tx *gorm.DB) {
+	version, ok := tx.InstanceGet(versionLockedKey)
+	if !ok || tx.Error != nil || tx.Statement.DryRun {
+		return
+	}
+
+	if tx.RowsAffected == 0 {
+		// restore the version loaded, the record was not updated
+		if field := tx.Statement.Schema.LookUpField("Version"); field != nil {
+			field.Set(tx.Statement.Context, tx.Statement.ReflectValue, version)
+		}
+
+		tx.AddError(ErrVersionConflict)
+	}
+}
@@ -0,0 +1,126 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-bolo/bolo/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type callbacksTestPost struct {
+	models.Base
+	models.SoftDelete
+	models.Authorship
+	models.Versioned
+
+	Title string
+}
+
+func getCallbacksTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.Nil(t, err)
+	assert.Nil(t, models.RegisterCallbacks(db))
+	assert.Nil(t, db.AutoMigrate(&callbacksTestPost{}))
+
+	return db
+}
+
+func TestAuthorship(t *testing.T) {
+	db := getCallbacksTestDB(t)
+
+	post := callbacksTestPost{Title: "first"}
+	err := db.WithContext(models.ContextWithAuthor(context.Background(), "10")).Create(&post).Error
+	assert.Nil(t, err)
+	assert.Equal(t, "10", post.CreatedBy)
+	assert.Equal(t, "10", post.UpdatedBy)
+
+	post.Title = "changed"
+	err = db.WithContext(models.ContextWithAuthor(context.Background(), "20")).Save(&post).Error
+	assert.Nil(t, err)
+
+	saved := callbacksTestPost{}
+	assert.Nil(t, db.First(&saved, post.ID).Error)
+	assert.Equal(t, "10", saved.CreatedBy)
+	assert.Equal(t, "20", saved.UpdatedBy)
+
+	t.Run("should not change the creator", func(t *testing.T) {
+		err := db.Model(&saved).Updates(map[string]interface{}{"created_by": "30"}).Error
+		assert.Nil(t, err)
+
+		reloaded := callbacksTestPost{}
+		assert.Nil(t, db.First(&reloaded, post.ID).Error)
+		assert.Equal(t, "10", reloaded.CreatedBy)
+	})
+
+	t.Run("should keep the columns without author", func(t *testing.T) {
+		err := db.Model(&saved).Update("title", "without author").Error
+		assert.Nil(t, err)
+		assert.Equal(t, "20", saved.UpdatedBy)
+	})
+}
+
+func TestVersioned(t *testing.T) {
+	db := getCallbacksTestDB(t)
+
+	posts := []callbacksTestPost{{Title: "a"}, {Title: "b"}}
+	assert.Nil(t, db.Create(&posts).Error)
+	assert.Equal(t, uint64(1), posts[0].Version)
+	assert.Equal(t, uint64(1), posts[1].Version)
+
+	first := callbacksTestPost{}
+	second := callbacksTestPost{}
+	assert.Nil(t, db.First(&first, posts[0].ID).Error)
+	assert.Nil(t, db.First(&second, posts[0].ID).Error)
+
+	first.Title = "first update"
+	assert.Nil(t, db.Save(&first).Error)
+	assert.Equal(t, uint64(2), first.Version)
+
+	t.Run("should fail updates with one old version", func(t *testing.T) {
+		second.Title = "second update"
+		err := db.Save(&second).Error
+		assert.ErrorIs(t, err, models.ErrVersionConflict)
+		assert.Equal(t, uint64(1), second.Version)
+
+		err = db.Model(&second).Updates(map[string]interface{}{"title": "second update"}).Error
+		assert.ErrorIs(t, err, models.ErrVersionConflict)
+
+		saved := callbacksTestPost{}
+		assert.Nil(t, db.First(&saved, posts[0].ID).Error)
+		assert.Equal(t, "first update", saved.Title)
+		assert.Equal(t, uint64(2), saved.Version)
+
+		var count int64
+		db.Model(&callbacksTestPost{}).Count(&count)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("should update after reload", func(t *testing.T) {
+		assert.Nil(t, db.First(&second, posts[0].ID).Error)
+		err := db.Model(&second).Updates(map[string]interface{}{"title": "second update"}).Error
+		assert.Nil(t, err)
+
+		saved := callbacksTestPost{}
+		assert.Nil(t, db.First(&saved, posts[0].ID).Error)
+		assert.Equal(t, "second update", saved.Title)
+		assert.Equal(t, uint64(3), saved.Version)
+	})
+}
+
+func TestSoftDelete(t *testing.T) {
+	db := getCallbacksTestDB(t)
+
+	post := callbacksTestPost{Title: "deleted"}
+	assert.Nil(t, db.Create(&post).Error)
+	assert.Nil(t, db.Delete(&post).Error)
+
+	err := db.First(&callbacksTestPost{}, post.ID).Error
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	deleted := callbacksTestPost{}
+	assert.Nil(t, db.Unscoped().First(&deleted, post.ID).Error)
+	assert.True(t, deleted.DeletedAt.Valid)
+}
@@ -0,0 +1,9 @@
+package models
+
+import "gorm.io/gorm"
+
+// SoftDelete - Embed in one model to mark records as deleted instead of removing them.
+// gorm skips deleted records in queries, use db.Unscoped() or the withDeleted query param to list them
+type SoftDelete struct {
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index" json:"deletedAt,omitempty"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "errors"
+
+var ErrVersionConflict = errors.New("models: record changed by other request, reload and try again")
+
+// Versioned - Embed in one model to enable optimistic locking. Updates only change the record if the
+// version is the same one loaded and fail with ErrVersionConflict if other request updated it before
+type Versioned struct {
+	Version uint64 `gorm:"column:version;not null;default:1" json:"version"`
+}
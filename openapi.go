@@ -0,0 +1,557 @@
+package bolo
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const OpenAPIVersion = "3.1.0"
+
+// Permission to read the OpenAPI document, served only with OPENAPI_ENABLED=true
+const PermissionViewOpenAPI = "view_openapi"
+
+// OpenAPIDocument - OpenAPI 3.1 document of the app resources and routes, served in /api/openapi.json
+type OpenAPIDocument struct {
+	OpenAPI    string                     `json:"openapi"`
+	Info       OpenAPIInfo                `json:"info"`
+	Paths      map[string]OpenAPIPathItem `json:"paths"`
+	Components OpenAPIComponents          `json:"components"`
+}
+
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type OpenAPIComponents struct {
+	Schemas map[string]*OpenAPISchema `json:"schemas"`
+}
+
+// OpenAPIPathItem - Operations of one path by lowercase HTTP method
+type OpenAPIPathItem map[string]*OpenAPIOperation
+
+type OpenAPIOperation struct {
+	Tags        []string                    `json:"tags,omitempty"`
+	Summary     string                      `json:"summary,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	OperationID string                      `json:"operationId,omitempty"`
+	Parameters  []*OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+}
+
+type OpenAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *OpenAPISchema `json:"schema"`
+}
+
+type OpenAPIRequestBody struct {
+	Required bool                         `json:"required,omitempty"`
+	Content  map[string]*OpenAPIMediaType `json:"content"`
+}
+
+type OpenAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*OpenAPIMediaType `json:"content,omitempty"`
+}
+
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPISchema - JSON schema subset used in the OpenAPI document
+type OpenAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	Items                *OpenAPISchema            `json:"items,omitempty"`
+	AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty"`
+	Enum                 []interface{}             `json:"enum,omitempty"`
+	MinLength            *int                      `json:"minLength,omitempty"`
+	MaxLength            *int                      `json:"maxLength,omitempty"`
+	Minimum              *float64                  `json:"minimum,omitempty"`
+	Maximum              *float64                  `json:"maximum,omitempty"`
+	MinItems             *int                      `json:"minItems,omitempty"`
+	MaxItems             *int                      `json:"maxItems,omitempty"`
+}
+
+const (
+	openAPIErrorSchema      = "ErrorResponse"
+	openAPIValidationSchema = "ValidationResponse"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+type openAPIGenerator struct {
+	app App
+	doc *OpenAPIDocument
+	// component names of the registered models
+	modelNames map[reflect.Type]string
+	// structs being reflected, to stop in recursive types
+	visiting map[reflect.Type]bool
+}
+
+// NewOpenAPIDocument - Generate the OpenAPI document of the resources set with App.SetResource and routes set with App.SetRoute.
+// Registered models are component schemas reflected from the JSON and validate tags
+func NewOpenAPIDocument(app App) *OpenAPIDocument {
+	cfg := app.GetConfiguration()
+
+	g := openAPIGenerator{
+		app: app,
+		doc: &OpenAPIDocument{
+			OpenAPI: OpenAPIVersion,
+			Info: OpenAPIInfo{
+				Title:       cfg.GetF("SITE_NAME", "bolo"),
+				Description: cfg.GetF("SITE_DESCRIPTION", ""),
+				Version:     cfg.GetF("OPENAPI_VERSION", "1.0.0"),
+			},
+			Paths: map[string]OpenAPIPathItem{},
+			Components: OpenAPIComponents{
+				Schemas: map[string]*OpenAPISchema{},
+			},
+		},
+		modelNames: map[reflect.Type]string{},
+		visiting:   map[reflect.Type]bool{},
+	}
+
+	schemas := app.GetModelSchemas()
+	for _, s := range schemas {
+		g.modelNames[reflect.Indirect(reflect.ValueOf(s.GetModel())).Type()] = s.Name
+	}
+	for _, s := range schemas {
+		g.doc.Components.Schemas[s.Name] = g.structSchema(reflect.Indirect(reflect.ValueOf(s.GetModel())).Type())
+	}
+
+	g.doc.Components.Schemas[openAPIErrorSchema] = g.typeSchema(reflect.TypeOf(BaseErrorResponse{}))
+	g.doc.Components.Schemas[openAPIValidationSchema] = g.typeSchema(reflect.TypeOf(ValidationResponse{}))
+
+	resources := app.GetResources()
+	names := []string{}
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		g.addResource(resources[name])
+	}
+
+	for _, route := range app.GetRoutes() {
+		g.addRoute(route)
+	}
+
+	return g.doc
+}
+
+func (g *openAPIGenerator) addOperation(method, path string, op *OpenAPIOperation) {
+	path, params := openAPIPath(path)
+	op.Parameters = append(params, op.Parameters...)
+
+	if op.Responses["default"] == nil {
+		op.Responses["default"] = openAPIErrorResponse("Error")
+	}
+
+	if g.doc.Paths[path] == nil {
+		g.doc.Paths[path] = OpenAPIPathItem{}
+	}
+	g.doc.Paths[path][strings.ToLower(method)] = op
+}
+
+func (g *openAPIGenerator) addResource(resource *HTTPResource) {
+	record := &OpenAPISchema{Type: "object"}
+	key := "record"
+	var filters []*OpenAPIParameter
+
+	if s := g.app.GetModelSchema(resource.ModelName); s != nil {
+		record = openAPIRef(s.Name)
+		key = s.Name
+		filters = openAPIFilterParams(s)
+	}
+
+	wrap := func(s *OpenAPISchema) *OpenAPISchema {
+		return &OpenAPISchema{
+			Type:       "object",
+			Properties: map[string]*OpenAPISchema{key: s},
+		}
+	}
+
+	list := wrap(&OpenAPISchema{Type: "array", Items: record})
+	list.Properties["meta"] = g.typeSchema(reflect.TypeOf(BaseMetaResponse{}))
+
+	body := &OpenAPIRequestBody{
+		Required: true,
+		Content:  openAPIJSONContent(wrap(record)),
+	}
+
+	tags := []string{resource.Name}
+	opID := func(name string) string { return resource.Name + "." + name }
+
+	g.addOperation(http.MethodGet, resource.Path, &OpenAPIOperation{
+		Tags:        tags,
+		Summary:     "List records",
+		OperationID: opID("query"),
+		Parameters:  append(openAPIPaginationParams(), filters...),
+		Responses: map[string]*OpenAPIResponse{
+			"200": {Description: "OK", Content: openAPIJSONContent(list)},
+		},
+	})
+	g.addOperation(http.MethodGet, resource.Path+"/count", &OpenAPIOperation{
+		Tags:        tags,
+		Summary:     "Count records",
+		OperationID: opID("count"),
+		Parameters:  filters,
+		Responses: map[string]*OpenAPIResponse{
+			"200": {Description: "OK", Content: openAPIJSONContent(g.typeSchema(reflect.TypeOf(BaseMetaResponse{})))},
+		},
+	})
+	g.addOperation(http.MethodPost, resource.Path, &OpenAPIOperation{
+		Tags:        tags,
+		Summary:     "Create one record",
+		OperationID: opID("create"),
+		RequestBody: body,
+		Responses: map[string]*OpenAPIResponse{
+			"201": {Description: "Created", Content: openAPIJSONContent(wrap(record))},
+			"422": openAPIValidationResponse(),
+		},
+	})
+	g.addOperation(http.MethodGet, resource.Path+"/:id", &OpenAPIOperation{
+		Tags:        tags,
+		Summary:     "Find one record",
+		OperationID: opID("findOne"),
+		Responses: map[string]*OpenAPIResponse{
+			"200": {Description: "OK", Content: openAPIJSONContent(wrap(record))},
+			"404": openAPIErrorResponse("Not Found"),
+		},
+	})
+
+	for _, method := range []string{http.MethodPost, http.MethodPatch, http.MethodPut} {
+		g.addOperation(method, resource.Path+"/:id", &OpenAPIOperation{
+			Tags:        tags,
+			Summary:     "Update one record",
+			OperationID: opID("update" + strings.ToUpper(method[:1]) + strings.ToLower(method[1:])),
+			RequestBody: body,
+			Responses: map[string]*OpenAPIResponse{
+				"200": {Description: "OK", Content: openAPIJSONContent(wrap(record))},
+				"404": openAPIErrorResponse("Not Found"),
+				"422": openAPIValidationResponse(),
+			},
+		})
+	}
+
+	g.addOperation(http.MethodDelete, resource.Path+"/:id", &OpenAPIOperation{
+		Tags:        tags,
+		Summary:     "Delete one record",
+		OperationID: opID("delete"),
+		Responses: map[string]*OpenAPIResponse{
+			"204": {Description: "No Content"},
+			"404": openAPIErrorResponse("Not Found"),
+		},
+	})
+}
+
+func (g *openAPIGenerator) addRoute(route *Route) {
+	op := OpenAPIOperation{
+		Summary:   route.Summary,
+		Responses: map[string]*OpenAPIResponse{},
+	}
+
+	ok := &OpenAPIResponse{Description: "OK"}
+	if route.Model != nil {
+		s := g.typeSchema(reflect.TypeOf(route.Model))
+		ok.Content = openAPIJSONContent(s)
+
+		if isUnsafeMethod(route.Method) {
+			op.RequestBody = &OpenAPIRequestBody{Content: openAPIJSONContent(s)}
+			op.Responses["422"] = openAPIValidationResponse()
+		}
+	}
+	op.Responses["200"] = ok
+
+	if route.Permission != "" {
+		op.Description = "Requires the " + route.Permission + " permission"
+		op.Responses["403"] = openAPIErrorResponse("Forbidden")
+	}
+
+	g.addOperation(route.Method, route.Prefix+route.Path, &op)
+}
+
+// Get the schema of one Go type, with references to the registered models
+func (g *openAPIGenerator) typeSchema(t reflect.Type) *OpenAPISchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if name, ok := g.modelNames[t]; ok {
+		return openAPIRef(name)
+	}
+
+	if t == timeType {
+		return &OpenAPISchema{Type: "string", Format: "date-time"}
+	}
+
+	// custom JSON encoding, ex: database.GeoPoint, can be any value
+	if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) {
+		return &OpenAPISchema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &OpenAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &OpenAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &OpenAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Float32:
+		return &OpenAPISchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &OpenAPISchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &OpenAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &OpenAPISchema{Type: "string", Format: "byte"}
+		}
+		return &OpenAPISchema{Type: "array", Items: g.typeSchema(t.Elem())}
+	case reflect.Map:
+		return &OpenAPISchema{Type: "object", AdditionalProperties: g.typeSchema(t.Elem())}
+	case reflect.Struct:
+		return g.structSchema(t)
+	default:
+		return &OpenAPISchema{}
+	}
+}
+
+// Get the object schema of one struct with the encoding/json field rules
+func (g *openAPIGenerator) structSchema(t reflect.Type) *OpenAPISchema {
+	s := &OpenAPISchema{Type: "object", Properties: map[string]*OpenAPISchema{}}
+
+	if g.visiting[t] {
+		return s
+	}
+	g.visiting[t] = true
+	defer delete(g.visiting, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := getJSONName(field)
+
+		if name == "" || !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		ft := field.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		// embedded structs without JSON name have the fields in the parent object
+		if _, hasName := field.Tag.Lookup("json"); field.Anonymous && !hasName && ft.Kind() == reflect.Struct {
+			embedded := g.structSchema(ft)
+			for k, v := range embedded.Properties {
+				s.Properties[k] = v
+			}
+			s.Required = append(s.Required, embedded.Required...)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		fs := g.typeSchema(field.Type)
+		if applyValidateTag(fs, field.Tag.Get("validate")) {
+			s.Required = append(s.Required, name)
+		}
+
+		s.Properties[name] = fs
+	}
+
+	return s
+}
+
+// Add the validator rules supported by JSON schema, return true for required fields
+func applyValidateTag(s *OpenAPISchema, tag string) bool {
+	required := false
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "required":
+			required = true
+		case "dive":
+			// next rules are of the items
+			return required
+		}
+
+		if s.Ref != "" {
+			continue
+		}
+
+		switch name {
+		case "email":
+			s.Format = "email"
+		case "url", "http_url", "uri":
+			s.Format = "uri"
+		case "uuid", "uuid4":
+			s.Format = "uuid"
+		case "min", "gte":
+			setOpenAPILimit(s, param, true)
+		case "max", "lte":
+			setOpenAPILimit(s, param, false)
+		case "len":
+			setOpenAPILimit(s, param, true)
+			setOpenAPILimit(s, param, false)
+		case "oneof":
+			for _, v := range strings.Fields(param) {
+				if n, err := strconv.ParseFloat(v, 64); err == nil && (s.Type == "integer" || s.Type == "number") {
+					s.Enum = append(s.Enum, n)
+				} else {
+					s.Enum = append(s.Enum, v)
+				}
+			}
+		}
+	}
+
+	return required
+}
+
+func setOpenAPILimit(s *OpenAPISchema, param string, isMin bool) {
+	switch s.Type {
+	case "string", "array":
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return
+		}
+
+		switch {
+		case s.Type == "string" && isMin:
+			s.MinLength = &n
+		case s.Type == "string":
+			s.MaxLength = &n
+		case isMin:
+			s.MinItems = &n
+		default:
+			s.MaxItems = &n
+		}
+	case "integer", "number":
+		n, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return
+		}
+
+		if isMin {
+			s.Minimum = &n
+		} else {
+			s.Maximum = &n
+		}
+	}
+}
+
+// Convert one echo path to the OpenAPI path template, ex: /urls/:id to /urls/{id}
+func openAPIPath(path string) (string, []*OpenAPIParameter) {
+	params := []*OpenAPIParameter{}
+	parts := strings.Split(path, "/")
+
+	for i, part := range parts {
+		name := ""
+		switch {
+		case strings.HasPrefix(part, ":"):
+			name = part[1:]
+		case part == "*":
+			name = "wildcard"
+		default:
+			continue
+		}
+
+		parts[i] = "{" + name + "}"
+		params = append(params, &OpenAPIParameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &OpenAPISchema{Type: "string"},
+		})
+	}
+
+	path = strings.Join(parts, "/")
+	if path == "" {
+		path = "/"
+	}
+
+	return path, params
+}
+
+func openAPIPaginationParams() []*OpenAPIParameter {
+	return []*OpenAPIParameter{
+		{Name: "limit", In: "query", Description: "Max number of records, up to PAGER_LIMIT_MAX", Schema: &OpenAPISchema{Type: "integer"}},
+		{Name: "page", In: "query", Description: "Page number, starting at 1", Schema: &OpenAPISchema{Type: "integer"}},
+	}
+}
+
+// Query params of the model fields with the filter tag
+func openAPIFilterParams(s *ModelSchema) []*OpenAPIParameter {
+	params := []*OpenAPIParameter{}
+
+	for _, field := range s.Fields {
+		if field.FilterParam == "" {
+			continue
+		}
+
+		params = append(params, &OpenAPIParameter{
+			Name:        field.FilterParam,
+			In:          "query",
+			Description: "Filter by " + field.Name + ", add one __operator suffix to use other operators, ex: " + field.FilterParam + "__not-equal",
+			Schema:      &OpenAPISchema{Type: "string"},
+		})
+	}
+
+	return params
+}
+
+func openAPIRef(name string) *OpenAPISchema {
+	return &OpenAPISchema{Ref: "#/components/schemas/" + name}
+}
+
+func openAPIJSONContent(s *OpenAPISchema) map[string]*OpenAPIMediaType {
+	return map[string]*OpenAPIMediaType{
+		"application/json": {Schema: s},
+	}
+}
+
+func openAPIErrorResponse(description string) *OpenAPIResponse {
+	return &OpenAPIResponse{
+		Description: description,
+		Content:     openAPIJSONContent(openAPIRef(openAPIErrorSchema)),
+	}
+}
+
+func openAPIValidationResponse() *OpenAPIResponse {
+	return &OpenAPIResponse{
+		Description: "Unprocessable Entity",
+		Content:     openAPIJSONContent(openAPIRef(openAPIValidationSchema)),
+	}
+}
+
+// Handler of /api/openapi.json
+func openAPIHandler(app App) Action {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, NewOpenAPIDocument(app))
+	}
+}
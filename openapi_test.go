@@ -0,0 +1,165 @@
+package bolo_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-bolo/bolo"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type openAPITestPost struct {
+	ID        uint64           `gorm:"primaryKey" json:"id" filter:"param:id;type:number"`
+	Title     string           `json:"title" validate:"required,min=3,max=100" filter:"param:title;type:string"`
+	Email     string           `json:"email,omitempty" validate:"omitempty,email"`
+	Status    string           `json:"status" validate:"oneof=draft published"`
+	Rating    int              `json:"rating" validate:"gte=1,lte=5"`
+	Tags      []string         `gorm:"serializer:json" json:"tags" validate:"max=10,dive,min=2"`
+	ParentID  *uint64          `json:"parentId"`
+	Parent    *openAPITestPost `json:"parent"`
+	Secret    string           `json:"-"`
+	CreatedAt time.Time        `json:"createdAt"`
+}
+
+type openAPITestController struct {
+	URLController
+}
+
+func (ctl *openAPITestController) GetModelName() string {
+	return "post"
+}
+
+func TestNewOpenAPIDocument(t *testing.T) {
+	t.Setenv("OPENAPI_ENABLED", "true")
+
+	app := GetTestApp()
+	assert.Nil(t, app.Bootstrap())
+	app.SetRolePermission("unAuthenticated", bolo.PermissionViewOpenAPI, true)
+	defer app.SetRolePermission("unAuthenticated", bolo.PermissionViewOpenAPI, false)
+
+	app.SetModel("post", &openAPITestPost{})
+	assert.NotNil(t, app.GetModelSchema("post"))
+	app.SetResource("post-api", &openAPITestController{}, app.SetRouterGroup("post-api", "/api/v1/posts"))
+	app.SetRoute(app.GetRouterGroup("main"), &bolo.Route{
+		Method:     http.MethodPost,
+		Path:       "posts/:id/publish",
+		Permission: "publish_post",
+		Summary:    "Publish one post",
+		Model:      &openAPITestPost{},
+		Action:     func(c echo.Context) error { return c.NoContent(http.StatusOK) },
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	app.GetRouter().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	doc := bolo.OpenAPIDocument{}
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, "3.1.0", doc.OpenAPI)
+
+	t.Run("should reflect the registered models with the validate tags", func(t *testing.T) {
+		post := doc.Components.Schemas["post"]
+		if !assert.NotNil(t, post) {
+			return
+		}
+
+		assert.Equal(t, []string{"title"}, post.Required)
+		assert.NotContains(t, post.Properties, "Secret")
+
+		title := post.Properties["title"]
+		assert.Equal(t, "string", title.Type)
+		assert.Equal(t, 3, *title.MinLength)
+		assert.Equal(t, 100, *title.MaxLength)
+
+		assert.Equal(t, "email", post.Properties["email"].Format)
+		assert.Equal(t, []interface{}{"draft", "published"}, post.Properties["status"].Enum)
+		assert.Equal(t, float64(1), *post.Properties["rating"].Minimum)
+		assert.Equal(t, float64(5), *post.Properties["rating"].Maximum)
+		assert.Equal(t, 10, *post.Properties["tags"].MaxItems)
+		assert.Nil(t, post.Properties["tags"].MinItems)
+		assert.Equal(t, "#/components/schemas/post", post.Properties["parent"].Ref)
+		assert.Equal(t, "date-time", post.Properties["createdAt"].Format)
+
+		assert.NotNil(t, doc.Components.Schemas["ErrorResponse"])
+		assert.NotNil(t, doc.Components.Schemas["ValidationResponse"])
+	})
+
+	t.Run("should add the resource operations", func(t *testing.T) {
+		list := doc.Paths["/api/v1/posts"]["get"]
+		if assert.NotNil(t, list) {
+			assert.Equal(t, "post-api.query", list.OperationID)
+
+			params := []string{}
+			for _, p := range list.Parameters {
+				params = append(params, p.Name)
+			}
+			assert.Equal(t, []string{"limit", "page", "id", "title"}, params)
+
+			records := list.Responses["200"].Content["application/json"].Schema.Properties["post"]
+			assert.Equal(t, "array", records.Type)
+			assert.Equal(t, "#/components/schemas/post", records.Items.Ref)
+		}
+
+		create := doc.Paths["/api/v1/posts"]["post"]
+		if assert.NotNil(t, create) {
+			assert.Equal(t, "#/components/schemas/post", create.RequestBody.Content["application/json"].Schema.Properties["post"].Ref)
+			assert.Equal(t, "#/components/schemas/ValidationResponse", create.Responses["422"].Content["application/json"].Schema.Ref)
+		}
+
+		for _, method := range []string{"get", "post", "patch", "put", "delete"} {
+			op := doc.Paths["/api/v1/posts/{id}"][method]
+			if assert.NotNil(t, op, method) {
+				assert.Equal(t, "id", op.Parameters[0].Name)
+				assert.Equal(t, "path", op.Parameters[0].In)
+				assert.Equal(t, "#/components/schemas/ErrorResponse", op.Responses["404"].Content["application/json"].Schema.Ref)
+			}
+		}
+
+		assert.NotNil(t, doc.Paths["/api/v1/posts/count"]["get"])
+	})
+
+	t.Run("should add the declarative routes", func(t *testing.T) {
+		op := doc.Paths["/posts/{id}/publish"]["post"]
+		if assert.NotNil(t, op) {
+			assert.Equal(t, "Publish one post", op.Summary)
+			assert.Contains(t, op.Description, "publish_post")
+			assert.NotNil(t, op.Responses["403"])
+			assert.Equal(t, "#/components/schemas/post", op.RequestBody.Content["application/json"].Schema.Ref)
+		}
+
+		assert.NotNil(t, doc.Paths["/api/models/{name}"]["get"])
+	})
+}
+
+func TestOpenAPIHandler_Access(t *testing.T) {
+	serve := func(app bolo.App) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil)
+		req.Header.Set(echo.HeaderAccept, "application/json")
+		rec := httptest.NewRecorder()
+		app.GetRouter().ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	t.Run("should be disabled by default", func(t *testing.T) {
+		app := GetTestApp()
+		assert.Nil(t, app.Bootstrap())
+		app.SetRolePermission("unAuthenticated", bolo.PermissionViewOpenAPI, true)
+		defer app.SetRolePermission("unAuthenticated", bolo.PermissionViewOpenAPI, false)
+
+		assert.Equal(t, http.StatusNotFound, serve(app))
+	})
+
+	t.Run("should return 403 without the view_openapi permission", func(t *testing.T) {
+		t.Setenv("OPENAPI_ENABLED", "true")
+
+		app := GetTestApp()
+		assert.Nil(t, app.Bootstrap())
+
+		assert.Equal(t, http.StatusForbidden, serve(app))
+	})
+}
@@ -0,0 +1,342 @@
+package bolo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-bolo/bolo/configuration"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	RateLimitTokenBucket   = "token-bucket"
+	RateLimitSlidingWindow = "sliding-window"
+
+	RateLimitByIP     = "ip"
+	RateLimitByUser   = "user"
+	RateLimitByAPIKey = "apiKey"
+)
+
+// RateLimitPolicy - Allow Limit requests in one Window for each client key
+type RateLimitPolicy struct {
+	// Policy name, used in store keys. Policies with the same name share the client counters
+	Name   string
+	Limit  int
+	Window time.Duration
+	// RateLimitTokenBucket (default) or RateLimitSlidingWindow
+	Algorithm string
+	// RateLimitByIP (default), RateLimitByUser or RateLimitByAPIKey. User and API key fallback to IP in anonymous requests
+	KeyBy string
+	// API key header used with RateLimitByAPIKey, default: X-API-Key
+	APIKeyHeader string
+}
+
+// RateLimitState - Client counters persisted by the RateLimitStore
+type RateLimitState struct {
+	// token bucket
+	Tokens    float64
+	UpdatedAt time.Time
+	// sliding window
+	WindowStart   time.Time
+	Count         int
+	PreviousCount int
+}
+
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration
+	RetryAfter time.Duration
+}
+
+// RateLimitStore - Store rate limit states. Update should load, change and save the state atomically
+type RateLimitStore interface {
+	Update(key string, ttl time.Duration, update func(state *RateLimitState) *RateLimitResult) (*RateLimitResult, error)
+}
+
+// Take - Consume one request from the client state
+func (p *RateLimitPolicy) Take(state *RateLimitState, now time.Time) *RateLimitResult {
+	if p.Algorithm == RateLimitSlidingWindow {
+		return p.takeSlidingWindow(state, now)
+	}
+
+	return p.takeTokenBucket(state, now)
+}
+
+func (p *RateLimitPolicy) takeTokenBucket(state *RateLimitState, now time.Time) *RateLimitResult {
+	capacity := float64(p.Limit)
+	// tokens per second
+	rate := capacity / p.Window.Seconds()
+
+	if state.UpdatedAt.IsZero() {
+		state.Tokens = capacity
+	} else {
+		state.Tokens = math.Min(capacity, state.Tokens+now.Sub(state.UpdatedAt).Seconds()*rate)
+	}
+	state.UpdatedAt = now
+
+	result := RateLimitResult{Limit: p.Limit}
+
+	if state.Tokens >= 1 {
+		state.Tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - state.Tokens) / rate)
+	}
+
+	result.Remaining = int(math.Floor(state.Tokens))
+	result.ResetAfter = secondsToDuration((capacity - state.Tokens) / rate)
+
+	return &result
+}
+
+func (p *RateLimitPolicy) takeSlidingWindow(state *RateLimitState, now time.Time) *RateLimitResult {
+	windowStart := now.Truncate(p.Window)
+
+	if !state.WindowStart.Equal(windowStart) {
+		if state.WindowStart.Equal(windowStart.Add(-p.Window)) {
+			state.PreviousCount = state.Count
+		} else {
+			state.PreviousCount = 0
+		}
+
+		state.Count = 0
+		state.WindowStart = windowStart
+	}
+
+	elapsed := now.Sub(windowStart)
+	weight := 1 - elapsed.Seconds()/p.Window.Seconds()
+	estimated := float64(state.PreviousCount)*weight + float64(state.Count)
+
+	result := RateLimitResult{
+		Limit:      p.Limit,
+		ResetAfter: p.Window - elapsed,
+	}
+
+	if estimated+1 <= float64(p.Limit) {
+		state.Count++
+		estimated++
+		result.Allowed = true
+	} else {
+		result.RetryAfter = p.Window - elapsed
+	}
+
+	result.Remaining = int(math.Max(0, math.Floor(float64(p.Limit)-estimated)))
+
+	return &result
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// Get the client key used to count requests
+func getRateLimitKey(ctx *RequestContext, policy *RateLimitPolicy) string {
+	switch policy.KeyBy {
+	case RateLimitByUser:
+		if ctx.IsAuthenticated && ctx.AuthenticatedUser != nil {
+			return policy.Name + ":user:" + ctx.AuthenticatedUser.GetID()
+		}
+	case RateLimitByAPIKey:
+		header := policy.APIKeyHeader
+		if header == "" {
+			header = "X-API-Key"
+		}
+
+		if key := ctx.Request().Header.Get(header); key != "" {
+			sum := sha256.Sum256([]byte(key))
+			return policy.Name + ":apiKey:" + hex.EncodeToString(sum[:])
+		}
+	}
+
+	return policy.Name + ":ip:" + ctx.RealIP()
+}
+
+// Get the policy for the request, route policies override router group policies
+func getRequestRateLimitPolicy(ctx *RequestContext) *RateLimitPolicy {
+	app := ctx.App
+
+	route := app.GetRoute(ctx.Request().Method, ctx.Path())
+	if route != nil && route.RateLimit != nil {
+		return route.RateLimit
+	}
+
+	return app.GetRouterGroupRateLimit(app.GetRouterGroupNameByPath(ctx.Request().URL.Path))
+}
+
+// Consume one request of the client or, without consume, only check if one request is allowed
+func updateRateLimit(app App, policy *RateLimitPolicy, key string, consume bool) (*RateLimitResult, error) {
+	now := app.GetClock().Now()
+
+	return app.GetRateLimitStore().Update(key, policy.Window, func(state *RateLimitState) *RateLimitResult {
+		if !consume {
+			preview := *state
+			return policy.Take(&preview, now)
+		}
+
+		return policy.Take(state, now)
+	})
+}
+
+// Set the rate limit headers and return the too many requests error if the request is not allowed
+func checkRateLimitResult(ctx *RequestContext, policy *RateLimitPolicy, key string, result *RateLimitResult) error {
+	header := ctx.Response().Header()
+	header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+	header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(int(math.Ceil(result.ResetAfter.Seconds()))))
+
+	if result.Allowed {
+		return nil
+	}
+
+	header.Set("Retry-After", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+
+	logrus.WithFields(logrus.Fields{
+		"policy": policy.Name,
+		"key":    key,
+		"path":   ctx.Path(),
+	}).Info("bolo.rateLimitMiddleware too many requests")
+
+	return &HTTPError{
+		Code:    http.StatusTooManyRequests,
+		Message: "Too Many Requests",
+	}
+}
+
+func logRateLimitStoreError(policy *RateLimitPolicy, err error) {
+	// don't block requests if the store is unavailable
+	logrus.WithFields(logrus.Fields{
+		"error":  fmt.Sprintf("%+v\n", err),
+		"policy": policy.Name,
+	}).Error("bolo.rateLimitMiddleware error on update rate limit state")
+}
+
+// Middleware that limit requests with the route or router group rate limit policies
+func rateLimitMiddleware(app App) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.(*RequestContext)
+
+			policy := getRequestRateLimitPolicy(ctx)
+			if policy == nil {
+				return next(ctx)
+			}
+
+			key := getRateLimitKey(ctx, policy)
+
+			result, err := updateRateLimit(app, policy, key, true)
+			if err != nil {
+				logRateLimitStoreError(policy, err)
+				return next(ctx)
+			}
+
+			if err := checkRateLimitResult(ctx, policy, key, result); err != nil {
+				return err
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+// Middleware that limit the failed authentications by IP with the route or router group rate limit policies.
+// Runs before the authentication, requests with invalid credentials don't reach the rateLimitMiddleware
+func authenticationRateLimitMiddleware(app App) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.(*RequestContext)
+
+			policy := getRequestRateLimitPolicy(ctx)
+			if policy == nil {
+				return next(ctx)
+			}
+
+			key := policy.Name + ":unauthorized:ip:" + ctx.RealIP()
+
+			result, err := updateRateLimit(app, policy, key, false)
+			if err != nil {
+				logRateLimitStoreError(policy, err)
+				return next(ctx)
+			}
+
+			if !result.Allowed {
+				return checkRateLimitResult(ctx, policy, key, result)
+			}
+
+			err = next(ctx)
+
+			var he HTTPErrorInterface
+			var ee *echo.HTTPError
+			if (errors.As(err, &he) && he.GetCode() == http.StatusUnauthorized) ||
+				(errors.As(err, &ee) && ee.Code == http.StatusUnauthorized) {
+				if _, storeErr := updateRateLimit(app, policy, key, true); storeErr != nil {
+					logRateLimitStoreError(policy, storeErr)
+				}
+			}
+
+			return err
+		}
+	}
+}
+
+// Get the client IP extractor, with TRUSTED_PROXIES (comma separated IP ranges, ex: 10.0.0.0/8) the IP is read
+// from the X-Forwarded-For header set by the trusted proxies. Without trusted proxies the IP is the connection address
+func newIPExtractorFromConfiguration(cfg configuration.ConfigurationInterface) echo.IPExtractor {
+	proxies := cfg.GetF("TRUSTED_PROXIES", "")
+	if proxies == "" {
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+
+	for _, proxy := range strings.Split(proxies, ",") {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+
+		if !strings.Contains(proxy, "/") {
+			if strings.Contains(proxy, ":") {
+				proxy += "/128"
+			} else {
+				proxy += "/32"
+			}
+		}
+
+		_, ipRange, err := net.ParseCIDR(proxy)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"proxy": proxy,
+				"error": err.Error(),
+			}).Error("bolo.newIPExtractorFromConfiguration invalid trusted proxy")
+			continue
+		}
+
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
+func newRateLimitStoreFromConfiguration(app App) RateLimitStore {
+	switch app.GetConfiguration().GetF("RATE_LIMIT_STORE", "memory") {
+	case "db":
+		return &DBRateLimitStore{App: app}
+	default:
+		return NewMemoryRateLimitStore(app)
+	}
+}
@@ -0,0 +1,132 @@
+package bolo
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MemoryRateLimitStore - Store rate limit states in the app process memory, use DBRateLimitStore with multiple app instances
+type MemoryRateLimitStore struct {
+	// App with the clock used in the expiration dates
+	App     App
+	mu      sync.Mutex
+	entries map[string]*memoryRateLimitEntry
+	// Update calls since the last expired entries cleanup
+	updates int
+}
+
+type memoryRateLimitEntry struct {
+	state     RateLimitState
+	expiresAt time.Time
+}
+
+func NewMemoryRateLimitStore(app App) *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		App:     app,
+		entries: map[string]*memoryRateLimitEntry{},
+	}
+}
+
+func (s *MemoryRateLimitStore) Update(key string, ttl time.Duration, update func(state *RateLimitState) *RateLimitResult) (*RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.App.GetClock().Now()
+
+	s.updates++
+	if s.updates > 1000 {
+		s.updates = 0
+		for k, e := range s.entries {
+			if e.expiresAt.Before(now) {
+				delete(s.entries, k)
+			}
+		}
+	}
+
+	entry := s.entries[key]
+	if entry == nil || entry.expiresAt.Before(now) {
+		entry = &memoryRateLimitEntry{}
+		s.entries[key] = entry
+	}
+
+	// keep the state while the previous window is used by the sliding window algorithm
+	entry.expiresAt = now.Add(2 * ttl)
+
+	return update(&entry.state), nil
+}
+
+// DBRateLimitStore - Store rate limit states in the bolo_rate_limits table, shared between app instances
+type DBRateLimitStore struct {
+	App App
+}
+
+func (s *DBRateLimitStore) Update(key string, ttl time.Duration, update func(state *RateLimitState) *RateLimitResult) (*RateLimitResult, error) {
+	var result *RateLimitResult
+
+	err := s.App.GetDB().Transaction(func(tx *gorm.DB) error {
+		now := s.App.GetClock().Now()
+
+		record := RateLimitModel{}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", key).
+			First(&record).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if record.ID == "" || record.ExpiresAt.Before(now) {
+			record = RateLimitModel{ID: key}
+		}
+
+		state := record.toState()
+		result = update(&state)
+
+		record.fromState(&state)
+		record.ExpiresAt = now.Add(2 * ttl)
+
+		return tx.Save(&record).Error
+	})
+
+	return result, err
+}
+
+// DeleteExpired - Remove expired rate limit states
+func (s *DBRateLimitStore) DeleteExpired() error {
+	return s.App.GetDB().Where("expires_at < ?", s.App.GetClock().Now()).Delete(&RateLimitModel{}).Error
+}
+
+type RateLimitModel struct {
+	ID            string    `gorm:"column:id;primaryKey;type:varchar(255)"`
+	Tokens        float64   `gorm:"column:tokens"`
+	UpdatedAt     time.Time `gorm:"column:updated_at;autoUpdateTime:false"`
+	WindowStart   time.Time `gorm:"column:window_start"`
+	Count         int       `gorm:"column:count"`
+	PreviousCount int       `gorm:"column:previous_count"`
+	ExpiresAt     time.Time `gorm:"column:expires_at;index"`
+}
+
+func (m *RateLimitModel) TableName() string {
+	return "bolo_rate_limits"
+}
+
+func (m *RateLimitModel) toState() RateLimitState {
+	return RateLimitState{
+		Tokens:        m.Tokens,
+		UpdatedAt:     m.UpdatedAt,
+		WindowStart:   m.WindowStart,
+		Count:         m.Count,
+		PreviousCount: m.PreviousCount,
+	}
+}
+
+func (m *RateLimitModel) fromState(state *RateLimitState) {
+	m.Tokens = state.Tokens
+	m.UpdatedAt = state.UpdatedAt
+	m.WindowStart = state.WindowStart
+	m.Count = state.Count
+	m.PreviousCount = state.PreviousCount
+}
@@ -0,0 +1,240 @@
+package bolo_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-bolo/bolo"
+	"github.com/go-bolo/clock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitPolicy_Take(t *testing.T) {
+	now, _ := time.Parse(time.RFC3339, "2023-07-16T10:00:00Z")
+
+	t.Run("token bucket should refill tokens over time", func(t *testing.T) {
+		policy := bolo.RateLimitPolicy{Limit: 2, Window: time.Minute}
+		state := bolo.RateLimitState{}
+
+		assert.True(t, policy.Take(&state, now).Allowed)
+		assert.True(t, policy.Take(&state, now).Allowed)
+
+		r := policy.Take(&state, now)
+		assert.False(t, r.Allowed)
+		assert.Equal(t, 0, r.Remaining)
+		assert.Equal(t, 30*time.Second, r.RetryAfter)
+
+		assert.True(t, policy.Take(&state, now.Add(30*time.Second)).Allowed)
+	})
+
+	t.Run("sliding window should weight the previous window", func(t *testing.T) {
+		policy := bolo.RateLimitPolicy{Limit: 4, Window: time.Minute, Algorithm: bolo.RateLimitSlidingWindow}
+		state := bolo.RateLimitState{}
+
+		for i := 0; i < 4; i++ {
+			assert.True(t, policy.Take(&state, now).Allowed)
+		}
+		assert.False(t, policy.Take(&state, now).Allowed)
+
+		// 50% of the previous window: 4 * 0.5 = 2 requests used
+		next := now.Add(90 * time.Second)
+		assert.True(t, policy.Take(&state, next).Allowed)
+		assert.True(t, policy.Take(&state, next).Allowed)
+		assert.False(t, policy.Take(&state, next).Allowed)
+	})
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	app := GetTestApp()
+	err := app.Bootstrap()
+	assert.Nil(t, err)
+
+	app.SetRoute(app.GetRouterGroup("main"), &bolo.Route{
+		Method:    http.MethodGet,
+		Path:      "limited",
+		RateLimit: &bolo.RateLimitPolicy{Limit: 1, Window: time.Minute},
+		Action: func(c echo.Context) error {
+			return c.String(http.StatusOK, "ok")
+		},
+	})
+
+	serve := func(ip, forwardedFor string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+		req.Header.Set(echo.HeaderAccept, "application/json")
+		req.RemoteAddr = ip + ":41000"
+		if forwardedFor != "" {
+			req.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+			req.Header.Set(echo.HeaderXRealIP, forwardedFor)
+		}
+		rec := httptest.NewRecorder()
+		app.GetRouter().ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("10.0.0.1", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+
+	rec = serve("10.0.0.1", "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+
+	rec = serve("10.0.0.2", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	t.Run("should ignore the forwarded headers of untrusted clients", func(t *testing.T) {
+		rec := serve("10.0.0.1", "203.0.113.10")
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	})
+}
+
+func TestApp_SetRoute_InvalidRateLimit(t *testing.T) {
+	app := GetTestApp()
+	assert.Nil(t, app.Bootstrap())
+
+	tests := []struct {
+		name   string
+		policy *bolo.RateLimitPolicy
+	}{
+		{name: "without window", policy: &bolo.RateLimitPolicy{Limit: 10}},
+		{name: "without limit", policy: &bolo.RateLimitPolicy{Window: time.Minute}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := app.SetRoute(app.GetRouterGroup("main"), &bolo.Route{
+				Method:    http.MethodGet,
+				Path:      "invalid-limit",
+				RateLimit: tt.policy,
+				Action: func(c echo.Context) error {
+					return c.String(http.StatusOK, "ok")
+				},
+			})
+			assert.NotNil(t, err)
+			assert.Nil(t, app.GetRoute(http.MethodGet, "/invalid-limit"))
+		})
+	}
+}
+
+func TestRateLimitMiddleware_TrustedProxies(t *testing.T) {
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8")
+
+	app := GetTestApp()
+	assert.Nil(t, app.Bootstrap())
+
+	app.SetRoute(app.GetRouterGroup("main"), &bolo.Route{
+		Method:    http.MethodGet,
+		Path:      "limited",
+		RateLimit: &bolo.RateLimitPolicy{Limit: 1, Window: time.Minute},
+		Action: func(c echo.Context) error {
+			return c.String(http.StatusOK, "ok")
+		},
+	})
+
+	serve := func(remoteAddr, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+		req.Header.Set(echo.HeaderAccept, "application/json")
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+		rec := httptest.NewRecorder()
+		app.GetRouter().ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve("10.0.0.5:41000", "203.0.113.10"))
+	assert.Equal(t, http.StatusTooManyRequests, serve("10.0.0.6:41000", "203.0.113.10"))
+	assert.Equal(t, http.StatusOK, serve("10.0.0.5:41000", "203.0.113.11"))
+	// forwarded headers of one untrusted address are ignored
+	assert.Equal(t, http.StatusOK, serve("198.51.100.1:41000", "203.0.113.10"))
+	assert.Equal(t, http.StatusTooManyRequests, serve("198.51.100.1:41000", "203.0.113.12"))
+}
+
+func TestRateLimitMiddleware_FailedAuthentication(t *testing.T) {
+	app := GetTestApp()
+	assert.Nil(t, app.Bootstrap())
+
+	app.SetRoute(app.GetRouterGroup("main"), &bolo.Route{
+		Method:     http.MethodGet,
+		Path:       "limited-by-user",
+		Permission: "view_limited",
+		RateLimit:  &bolo.RateLimitPolicy{Limit: 2, Window: time.Minute, KeyBy: bolo.RateLimitByUser},
+		Action: func(c echo.Context) error {
+			return c.String(http.StatusOK, "ok")
+		},
+	})
+
+	serve := func(remoteAddr string) int {
+		req := httptest.NewRequest(http.MethodGet, "/limited-by-user", nil)
+		req.Header.Set(echo.HeaderAccept, "application/json")
+		req.Header.Set(echo.HeaderAuthorization, "Bearer invalid")
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		app.GetRouter().ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, serve("10.0.0.1:41000"))
+	assert.Equal(t, http.StatusUnauthorized, serve("10.0.0.1:41000"))
+	assert.Equal(t, http.StatusTooManyRequests, serve("10.0.0.1:41000"))
+	assert.Equal(t, http.StatusUnauthorized, serve("10.0.0.2:41000"))
+}
+
+func TestDBRateLimitStore(t *testing.T) {
+	app := GetTestApp()
+	err := app.Bootstrap()
+	assert.Nil(t, err)
+
+	err = app.GetDB().AutoMigrate(&bolo.RateLimitModel{})
+	assert.Nil(t, err)
+
+	store := bolo.DBRateLimitStore{App: app}
+	policy := bolo.RateLimitPolicy{Name: "login", Limit: 1, Window: time.Minute}
+	now := app.GetClock().Now()
+
+	take := func(state *bolo.RateLimitState) *bolo.RateLimitResult {
+		return policy.Take(state, now)
+	}
+
+	r, err := store.Update("login:ip:10.0.0.1", policy.Window, take)
+	assert.Nil(t, err)
+	assert.True(t, r.Allowed)
+
+	r, err = store.Update("login:ip:10.0.0.1", policy.Window, take)
+	assert.Nil(t, err)
+	assert.False(t, r.Allowed)
+}
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	app := GetTestApp()
+	c := clock.NewMock()
+	c.Set(time.Date(2023, 7, 16, 10, 0, 0, 0, time.UTC))
+	app.SetClock(c)
+
+	store := bolo.NewMemoryRateLimitStore(app)
+	policy := bolo.RateLimitPolicy{Name: "login", Limit: 1, Window: time.Minute}
+
+	take := func(state *bolo.RateLimitState) *bolo.RateLimitResult {
+		return policy.Take(state, c.Now())
+	}
+
+	r, err := store.Update("login:ip:10.0.0.1", policy.Window, take)
+	assert.Nil(t, err)
+	assert.True(t, r.Allowed)
+
+	r, err = store.Update("login:ip:10.0.0.1", policy.Window, take)
+	assert.Nil(t, err)
+	assert.False(t, r.Allowed)
+
+	// the state expires after two windows of the app clock
+	c.Add(2*time.Minute + time.Second)
+
+	r, err = store.Update("login:ip:10.0.0.1", policy.Window, func(state *bolo.RateLimitState) *bolo.RateLimitResult {
+		assert.True(t, state.UpdatedAt.IsZero())
+		return take(state)
+	})
+	assert.Nil(t, err)
+	assert.True(t, r.Allowed)
+}
@@ -3,6 +3,7 @@ package bolo
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
@@ -42,6 +43,14 @@ type Route struct {
 	Layout     string
 	Theme      string
 	Model      interface{}
+	// Short description of the route in the OpenAPI document
+	Summary string
+	// Disable CSRF token validation in this route
+	SkipCSRF bool
+	// Rate limit policy, overrides the router group policy
+	RateLimit *RateLimitPolicy
+	// Max duration of the queries made with RequestContext.DB(), overrides the DB_QUERY_TIMEOUT configuration
+	DBTimeout time.Duration
 }
 
 // NegotiateContentType returns the best offered content type for the request's
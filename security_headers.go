@@ -0,0 +1,114 @@
+package bolo
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/go-bolo/bolo/configuration"
+	"github.com/labstack/echo/v4"
+)
+
+// Placeholder replaced by the request nonce in the Content-Security-Policy
+const CSPNoncePlaceholder = "{nonce}"
+
+// Key of the request CSP nonce in the echo context, available in error pages rendered with other request contexts
+const CSPNonceKey = "cspNonce"
+
+// SecurityHeadersOptions - Security response headers. Empty values disable the header
+type SecurityHeadersOptions struct {
+	// Strict-Transport-Security max-age in seconds, only sent in https apps
+	HSTSMaxAge            int
+	HSTSIncludeSubdomains bool
+	HSTSPreload           bool
+	// Send X-Content-Type-Options: nosniff
+	ContentTypeNosniff bool
+	FrameOptions       string
+	ReferrerPolicy     string
+	PermissionsPolicy  string
+	// Content-Security-Policy, {nonce} is replaced with the request CSP nonce
+	ContentSecurityPolicy string
+	// Send the policy as Content-Security-Policy-Report-Only, the default to not break apps with inline scripts.
+	// Set SECURITY_CSP_REPORT_ONLY=false to enforce the policy
+	CSPReportOnly bool
+}
+
+// NewSecurityHeadersOptionsFromConfiguration - Build security header options from SECURITY_* configuration variables
+func NewSecurityHeadersOptionsFromConfiguration(cfg configuration.ConfigurationInterface) *SecurityHeadersOptions {
+	return &SecurityHeadersOptions{
+		HSTSMaxAge:            cfg.GetIntF("SECURITY_HSTS_MAX_AGE", 31536000),
+		HSTSIncludeSubdomains: cfg.GetBoolF("SECURITY_HSTS_INCLUDE_SUBDOMAINS", false),
+		HSTSPreload:           cfg.GetBoolF("SECURITY_HSTS_PRELOAD", false),
+		ContentTypeNosniff:    cfg.GetBoolF("SECURITY_CONTENT_TYPE_NOSNIFF", true),
+		FrameOptions:          cfg.GetF("SECURITY_FRAME_OPTIONS", "SAMEORIGIN"),
+		ReferrerPolicy:        cfg.GetF("SECURITY_REFERRER_POLICY", "strict-origin-when-cross-origin"),
+		PermissionsPolicy:     cfg.GetF("SECURITY_PERMISSIONS_POLICY", "camera=(), microphone=(), geolocation=()"),
+		ContentSecurityPolicy: cfg.GetF("SECURITY_CSP", "default-src 'self'; script-src 'self' 'nonce-{nonce}'; style-src 'self' 'unsafe-inline'; img-src 'self' data: https:; object-src 'none'; base-uri 'self'; frame-ancestors 'self'"),
+		CSPReportOnly:         cfg.GetBoolF("SECURITY_CSP_REPORT_ONLY", true),
+	}
+}
+
+// GetCSPNonce - Get the Content-Security-Policy nonce of the current request
+func (r *RequestContext) GetCSPNonce() string {
+	if r.CSPNonce == "" && r.echoContext != nil {
+		nonce, _ := r.Get(CSPNonceKey).(string)
+		return nonce
+	}
+
+	return r.CSPNonce
+}
+
+// Middleware that sets the security response headers and the request CSP nonce
+func securityHeadersMiddleware(app App) echo.MiddlewareFunc {
+	opts := NewSecurityHeadersOptionsFromConfiguration(app.GetConfiguration())
+
+	hsts := ""
+	if opts.HSTSMaxAge > 0 && app.GetConfiguration().GetF("PROTOCOL", "http") == "https" {
+		hsts = "max-age=" + strconv.Itoa(opts.HSTSMaxAge)
+		if opts.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if opts.HSTSPreload {
+			hsts += "; preload"
+		}
+	}
+
+	cspHeader := echo.HeaderContentSecurityPolicy
+	if opts.CSPReportOnly {
+		cspHeader = echo.HeaderContentSecurityPolicyReportOnly
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.(*RequestContext)
+			header := ctx.Response().Header()
+
+			if hsts != "" {
+				header.Set(echo.HeaderStrictTransportSecurity, hsts)
+			}
+			if opts.ContentTypeNosniff {
+				header.Set(echo.HeaderXContentTypeOptions, "nosniff")
+			}
+			if opts.FrameOptions != "" {
+				header.Set(echo.HeaderXFrameOptions, opts.FrameOptions)
+			}
+			if opts.ReferrerPolicy != "" {
+				header.Set(echo.HeaderReferrerPolicy, opts.ReferrerPolicy)
+			}
+			if opts.PermissionsPolicy != "" {
+				header.Set("Permissions-Policy", opts.PermissionsPolicy)
+			}
+
+			if opts.ContentSecurityPolicy != "" {
+				ctx.CSPNonce = RandomToken(16)
+				ctx.Set(CSPNonceKey, ctx.CSPNonce)
+				header.Set(cspHeader, strings.ReplaceAll(opts.ContentSecurityPolicy, CSPNoncePlaceholder, ctx.CSPNonce))
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+func cspNonce(ctx *RequestContext) string {
+	return ctx.GetCSPNonce()
+}
@@ -0,0 +1,101 @@
+package bolo_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/go-bolo/bolo"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityHeadersMiddleware(t *testing.T) {
+	os.Setenv("PROTOCOL", "https")
+	defer os.Unsetenv("PROTOCOL")
+
+	app := GetTestApp()
+	err := app.Bootstrap()
+	assert.Nil(t, err)
+
+	var nonce string
+	app.GetRouter().GET("/secure", func(c echo.Context) error {
+		nonce = c.(*bolo.RequestContext).GetCSPNonce()
+		return c.String(http.StatusOK, "ok")
+	})
+
+	serve := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/secure", nil)
+		rec := httptest.NewRecorder()
+		app.GetRouter().ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve()
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "max-age=31536000", rec.Header().Get(echo.HeaderStrictTransportSecurity))
+	assert.Equal(t, "nosniff", rec.Header().Get(echo.HeaderXContentTypeOptions))
+	assert.Equal(t, "strict-origin-when-cross-origin", rec.Header().Get(echo.HeaderReferrerPolicy))
+	assert.NotEmpty(t, rec.Header().Get("Permissions-Policy"))
+
+	assert.NotEmpty(t, nonce)
+	assert.Empty(t, rec.Header().Get(echo.HeaderContentSecurityPolicy), "should not enforce the policy by default")
+	csp := rec.Header().Get(echo.HeaderContentSecurityPolicyReportOnly)
+	assert.True(t, strings.Contains(csp, "'nonce-"+nonce+"'"), csp)
+
+	firstNonce := nonce
+	serve()
+	assert.NotEqual(t, firstNonce, nonce, "should generate one nonce per request")
+}
+
+func TestSecurityHeadersMiddleware_EnforceCSP(t *testing.T) {
+	t.Setenv("SECURITY_CSP_REPORT_ONLY", "false")
+
+	app := GetTestApp()
+	assert.Nil(t, app.Bootstrap())
+
+	app.GetRouter().GET("/secure", func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/secure", nil)
+	rec := httptest.NewRecorder()
+	app.GetRouter().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get(echo.HeaderContentSecurityPolicy))
+	assert.Empty(t, rec.Header().Get(echo.HeaderContentSecurityPolicyReportOnly))
+}
+
+func TestSecurityHeadersMiddleware_ErrorPageNonce(t *testing.T) {
+	app := GetTestApp()
+	setTestTemplates(t, map[string]string{
+		"site/html.html":            `{{ .Ctx.Content }}`,
+		"site/layouts/default.html": `<script nonce="{{ cspNonce .Ctx }}"></script>{{ .Ctx.Content }}`,
+		"site/404.html":             `not found`,
+	})
+	assert.Nil(t, app.Bootstrap())
+
+	req := httptest.NewRequest(http.MethodGet, "/not-found", nil)
+	req.Header.Set(echo.HeaderAccept, "text/html")
+	rec := httptest.NewRecorder()
+	app.GetRouter().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	csp := rec.Header().Get(echo.HeaderContentSecurityPolicyReportOnly)
+	nonce := strings.TrimPrefix(regexp.MustCompile(`'nonce-[^']+`).FindString(csp), "'nonce-")
+	if assert.NotEmpty(t, nonce) {
+		assert.Equal(t, `<script nonce="`+nonce+`"></script>not found`, rec.Body.String())
+	}
+
+	t.Run("should get the nonce of the echo context in new request contexts", func(t *testing.T) {
+		c := app.GetRouter().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+		c.Set(bolo.CSPNonceKey, "abc")
+
+		ctx := bolo.NewRequestContext(&bolo.RequestContextOpts{App: app, EchoContext: c})
+		assert.Equal(t, "abc", ctx.GetCSPNonce())
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/go-bolo/bolo/models"
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
@@ -62,22 +63,64 @@ func (e *HTTPError) SetInternal(internal error) error {
 	return nil
 }
 
+// handledError - Error already written in the response by the HTTP error handler, see handleErrorOnce
+type handledError struct {
+	err error
+}
+
+func (e *handledError) Error() string {
+	return e.err.Error()
+}
+
+func (e *handledError) Unwrap() error {
+	return e.err
+}
+
+// Run the app HTTP error handler with the request context, with the session, user and CSP nonce of the request,
+// and return the error marked as handled. Outer middlewares get the error and don't write the response again
+func handleErrorOnce(ctx *RequestContext, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var handled *handledError
+	if errors.As(err, &handled) {
+		return err
+	}
+
+	ctx.Error(err)
+
+	return &handledError{err: err}
+}
+
 type ValidationResponse struct {
 	Errors []*ValidationFieldError `json:"errors"`
 }
 
 type ValidationFieldError struct {
-	Field   string `json:"field"`
-	Tag     string `json:"tag"`
-	Value   string `json:"value"`
+	// JSON path of the field, ex: url.tags[0]
+	Field string `json:"field"`
+	Tag   string `json:"tag"`
+	// Validation tag param, ex: 10 in max=10
+	Param string `json:"param,omitempty"`
+	// Invalid value
+	Value interface{} `json:"value"`
+	// Message in the request language
 	Message string `json:"message"`
 }
 
 func CustomHTTPErrorHandler(app App) func(err error, c echo.Context) {
+	httpErrors := newHTTPErrorsMetric(app)
+
 	return func(err error, c echo.Context) {
-		logrus.WithFields(logrus.Fields{
-			"err": fmt.Sprintf("%+v\n", err),
-		}).Debug("bolo.CustomHTTPErrorHandler running")
+		var handled *handledError
+		if errors.As(err, &handled) {
+			return
+		}
+
+		defer func() {
+			httpErrors.Inc(strconv.Itoa(c.Response().Status))
+		}()
 
 		var ctx *RequestContext
 
@@ -88,6 +131,18 @@ func CustomHTTPErrorHandler(app App) func(err error, c echo.Context) {
 			ctx = NewRequestContext(&RequestContextOpts{App: app, EchoContext: c})
 		}
 
+		ctx.Log().WithFields(logrus.Fields{
+			"err": fmt.Sprintf("%+v\n", err),
+		}).Debug("bolo.CustomHTTPErrorHandler running")
+
+		if errors.Is(err, models.ErrVersionConflict) {
+			err = &HTTPError{
+				Code:     http.StatusConflict,
+				Message:  "Conflict",
+				Internal: err,
+			}
+		}
+
 		app.GetEvents().Trigger("http-error", map[string]any{
 			"error":       err,
 			"echoContext": c,
@@ -115,7 +170,8 @@ func CustomHTTPErrorHandler(app App) func(err error, c echo.Context) {
 			}
 		}
 
-		if ve, ok := err.(validator.ValidationErrors); ok {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
 			validationError(ve, err, ctx)
 			return
 		}
@@ -133,10 +189,14 @@ func CustomHTTPErrorHandler(app App) func(err error, c echo.Context) {
 			forbiddenErrorHandler(err, ctx)
 		case 404:
 			notFoundErrorHandler(err, ctx)
+		case 409:
+			conflictErrorHandler(err, ctx)
+		case 429:
+			tooManyRequestsErrorHandler(err, ctx)
 		case 500:
 			internalServerErrorHandler(err, ctx)
 		default:
-			logrus.WithFields(logrus.Fields{
+			ctx.Log().WithFields(logrus.Fields{
 				"error":             err,
 				"statusCode":        code,
 				"path":              c.Path(),
@@ -166,11 +226,11 @@ func forbiddenErrorHandler(err error, c echo.Context) error {
 		}
 	}
 
-	logrus.WithFields(logParams).Debug("bolo.forbiddenErrorHandler running")
+	ctx.Log().WithFields(logParams).Debug("bolo.forbiddenErrorHandler running")
 
 	switch ctx.GetResponseContentType() {
 	case "text/html":
-		ctx.Title = "Acesso restrito"
+		ctx.Title = ctx.T("bolo.error.forbidden")
 
 		if err := c.Render(http.StatusForbidden, "403", &TemplateCTX{
 			Ctx: ctx,
@@ -191,14 +251,14 @@ func badRequestErrorHandler(err error, ctx *RequestContext) error {
 		status = ctx.Get("status").(int)
 	}
 
-	logrus.WithFields(logrus.Fields{
+	ctx.Log().WithFields(logrus.Fields{
 		"err":  fmt.Sprintf("%+v\n", err),
 		"code": status,
 	}).Debug("bolo.badRequestErrorHandler running")
 
 	switch ctx.GetResponseContentType() {
 	case "text/html":
-		ctx.Title = "Bad request"
+		ctx.Title = ctx.T("bolo.error.badRequest")
 
 		template := "400"
 		if ctx.Get("template") != nil {
@@ -219,7 +279,7 @@ func badRequestErrorHandler(err error, ctx *RequestContext) error {
 }
 
 func unAuthorizedErrorHandler(err error, ctx *RequestContext) error {
-	logrus.WithFields(logrus.Fields{
+	ctx.Log().WithFields(logrus.Fields{
 		"err":               fmt.Sprintf("%+v\n", err),
 		"code":              "401",
 		"path":              ctx.Path(),
@@ -230,7 +290,7 @@ func unAuthorizedErrorHandler(err error, ctx *RequestContext) error {
 
 	switch ctx.GetResponseContentType() {
 	case "text/html":
-		ctx.Title = "Forbidden"
+		ctx.Title = ctx.T("bolo.error.unauthorized")
 
 		if err := ctx.Render(http.StatusUnauthorized, "401", &TemplateCTX{
 			Ctx: ctx,
@@ -247,14 +307,14 @@ func unAuthorizedErrorHandler(err error, ctx *RequestContext) error {
 }
 
 func notFoundErrorHandler(err error, ctx *RequestContext) error {
-	logrus.WithFields(logrus.Fields{
+	ctx.Log().WithFields(logrus.Fields{
 		"err":  fmt.Sprintf("%+v\n", err),
 		"code": "404",
 	}).Debug("bolo.notFoundErrorHandler running")
 
 	switch ctx.GetResponseContentType() {
 	case "text/html":
-		ctx.Title = "Não encontrado"
+		ctx.Title = ctx.T("bolo.error.notFound")
 
 		if err := ctx.Render(http.StatusNotFound, "404", &TemplateCTX{
 			Ctx: ctx,
@@ -268,34 +328,71 @@ func notFoundErrorHandler(err error, ctx *RequestContext) error {
 	}
 }
 
+func conflictErrorHandler(err error, ctx *RequestContext) error {
+	ctx.Log().WithFields(logrus.Fields{
+		"err":  fmt.Sprintf("%+v\n", err),
+		"code": "409",
+		"path": ctx.Path(),
+	}).Debug("bolo.conflictErrorHandler running")
+
+	switch ctx.GetResponseContentType() {
+	case "text/html":
+		ctx.Title = ctx.T("bolo.error.conflict")
+
+		if err := ctx.Render(http.StatusConflict, "409", &TemplateCTX{
+			Ctx: ctx,
+		}); err != nil {
+			ctx.Logger().Error(err)
+		}
+		return nil
+	default:
+		ctx.JSON(http.StatusConflict, &HTTPError{Code: http.StatusConflict, Message: "Conflict"})
+		return nil
+	}
+}
+
+func tooManyRequestsErrorHandler(err error, ctx *RequestContext) error {
+	ctx.Log().WithFields(logrus.Fields{
+		"err":  fmt.Sprintf("%+v\n", err),
+		"code": "429",
+		"path": ctx.Path(),
+	}).Debug("bolo.tooManyRequestsErrorHandler running")
+
+	switch ctx.GetResponseContentType() {
+	case "text/html":
+		ctx.Title = ctx.T("bolo.error.tooManyRequests")
+
+		if err := ctx.Render(http.StatusTooManyRequests, "429", &TemplateCTX{
+			Ctx: ctx,
+		}); err != nil {
+			ctx.Logger().Error(err)
+		}
+		return nil
+	default:
+		ctx.JSON(http.StatusTooManyRequests, &HTTPError{Code: http.StatusTooManyRequests, Message: "Too Many Requests"})
+		return nil
+	}
+}
+
 func validationError(ve validator.ValidationErrors, err error, ctx *RequestContext) error {
 	status := http.StatusUnprocessableEntity
 	if ctx.Get("status") != nil {
 		status = ctx.Get("status").(int)
 	}
 
-	logrus.WithFields(logrus.Fields{
+	ctx.Log().WithFields(logrus.Fields{
 		"err":  fmt.Sprintf("%+v\n", err),
 		"code": status,
 	}).Debug("bolo.validationError running")
 
-	resp := ValidationResponse{}
-
-	if err != nil {
-		for _, err := range err.(validator.ValidationErrors) {
-			var el ValidationFieldError
-			el.Field = err.Field()
-			el.Tag = err.Tag()
-			el.Value = err.Param()
-			el.Message = err.Error()
-			resp.Errors = append(resp.Errors, &el)
-		}
+	resp := ValidationResponse{
+		Errors: parseValidationErrors(ctx, ve),
 	}
 
 	switch ctx.GetResponseContentType() {
 	case "text/html":
-		if ctx.Title != "" {
-			ctx.Title = "Bad request"
+		if ctx.Title == "" {
+			ctx.Title = ctx.T("bolo.error.badRequest")
 		}
 
 		template := "400"
@@ -304,7 +401,8 @@ func validationError(ve validator.ValidationErrors, err error, ctx *RequestConte
 		}
 
 		if err := ctx.Render(status, template, &TemplateCTX{
-			Ctx: ctx,
+			Ctx:              ctx,
+			ValidationErrors: resp.Errors,
 		}); err != nil {
 			ctx.Logger().Error(err)
 		}
@@ -321,7 +419,7 @@ func internalServerErrorHandler(err error, ctx *RequestContext) error {
 		code = he.Code
 	}
 
-	logrus.WithFields(logrus.Fields{
+	ctx.Log().WithFields(logrus.Fields{
 		"err":               fmt.Sprintf("%+v\n", err),
 		"code":              code,
 		"path":              ctx.Path(),
@@ -332,7 +430,7 @@ func internalServerErrorHandler(err error, ctx *RequestContext) error {
 
 	switch ctx.GetResponseContentType() {
 	case "text/html":
-		ctx.Title = "Internal server error"
+		ctx.Title = ctx.T("bolo.error.internalServerError")
 
 		if err := ctx.Render(http.StatusInternalServerError, "500", &TemplateCTX{
 			Ctx: ctx,
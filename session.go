@@ -0,0 +1,255 @@
+package bolo
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// Session key where the authenticated user id is stored
+const SessionUserIDKey = "userID"
+
+// SessionStore - Persist sessions. The value returned by Save is signed and stored in the session cookie,
+// server side stores return the session id and the cookie store returns the encoded session.
+type SessionStore interface {
+	// Load one session from the cookie value, returns nil without error if the session not exists
+	Load(ctx *RequestContext, value string) (*Session, error)
+	// Save the session and return the cookie value
+	Save(ctx *RequestContext, session *Session) (string, error)
+	Delete(ctx *RequestContext, session *Session) error
+}
+
+// Session - Request session data, values should be JSON serializable
+type Session struct {
+	ID           string                 `json:"id"`
+	Values       map[string]interface{} `json:"values"`
+	CreatedAt    time.Time              `json:"createdAt"`
+	LastAccessAt time.Time              `json:"lastAccessAt"`
+
+	store     SessionStore
+	changed   bool
+	destroyed bool
+	// ids removed with Regenerate, deleted from store on save
+	oldIDs []string
+}
+
+// NewSession - Create a new empty session
+func NewSession(now time.Time) *Session {
+	return &Session{
+		ID:           RandomToken(32),
+		Values:       map[string]interface{}{},
+		CreatedAt:    now,
+		LastAccessAt: now,
+	}
+}
+
+func (s *Session) Get(key string) interface{} {
+	return s.Values[key]
+}
+
+func (s *Session) GetString(key string) string {
+	if v, ok := s.Values[key].(string); ok {
+		return v
+	}
+
+	return ""
+}
+
+func (s *Session) Set(key string, value interface{}) {
+	s.Values[key] = value
+	s.changed = true
+	s.destroyed = false
+}
+
+func (s *Session) Delete(key string) {
+	if _, ok := s.Values[key]; !ok {
+		return
+	}
+
+	delete(s.Values, key)
+	s.changed = true
+}
+
+// Regenerate - Change the session id keeping the values, use it on login to prevent session fixation
+func (s *Session) Regenerate() error {
+	s.oldIDs = append(s.oldIDs, s.ID)
+	s.ID = RandomToken(32)
+	s.changed = true
+
+	return nil
+}
+
+// Destroy - Remove all session values, delete it from the store and expire the session cookie
+func (s *Session) Destroy() error {
+	s.oldIDs = append(s.oldIDs, s.ID)
+	s.ID = RandomToken(32)
+	s.Values = map[string]interface{}{}
+	s.destroyed = true
+	s.changed = true
+
+	return nil
+}
+
+// IsNew - Returns true if the session was not loaded from the store
+func (s *Session) IsNew() bool {
+	return s.store == nil
+}
+
+func getSessionCookieName(app App) string {
+	return app.GetConfiguration().GetF("SESSION_COOKIE_NAME", "bolo_session")
+}
+
+func getSessionTimeouts(app App) (idle, absolute time.Duration) {
+	cfg := app.GetConfiguration()
+	idle = time.Duration(cfg.GetInt64F("SESSION_IDLE_TIMEOUT", 7200)) * time.Second
+	absolute = time.Duration(cfg.GetInt64F("SESSION_ABSOLUTE_TIMEOUT", 1209600)) * time.Second
+
+	return idle, absolute
+}
+
+// Load the request session from the signed session cookie
+func loadSession(ctx *RequestContext) *Session {
+	app := ctx.App
+	store := app.GetSessionStore()
+	now := app.GetClock().Now()
+
+	cookie, err := ctx.Cookie(getSessionCookieName(app))
+	if err != nil || cookie.Value == "" {
+		return NewSession(now)
+	}
+
+	value, err := VerifySignedValue(app.GetSecret(), cookie.Value)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"path": ctx.Request().URL.Path,
+		}).Debug("bolo.loadSession invalid session cookie signature")
+		return NewSession(now)
+	}
+
+	session, err := store.Load(ctx, value)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"error": fmt.Sprintf("%+v\n", err),
+		}).Warn("bolo.loadSession error on load session")
+		return NewSession(now)
+	}
+
+	if session == nil {
+		return NewSession(now)
+	}
+
+	session.store = store
+
+	idle, absolute := getSessionTimeouts(app)
+	if now.Sub(session.LastAccessAt) > idle || now.Sub(session.CreatedAt) > absolute {
+		session.Destroy()
+		return session
+	}
+
+	// only touch the session once a minute to avoid writes in every request
+	if now.Sub(session.LastAccessAt) > time.Minute {
+		session.LastAccessAt = now
+		session.changed = true
+	}
+
+	return session
+}
+
+// Persist the session changes and write the session cookie
+func saveSession(ctx *RequestContext, session *Session) error {
+	if !session.changed {
+		return nil
+	}
+	session.changed = false
+
+	app := ctx.App
+	store := app.GetSessionStore()
+
+	for _, id := range session.oldIDs {
+		err := store.Delete(ctx, &Session{ID: id})
+		if err != nil {
+			return err
+		}
+	}
+	session.oldIDs = nil
+
+	cookie := &http.Cookie{
+		Name:     getSessionCookieName(app),
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   ctx.Protocol == "https",
+		SameSite: http.SameSiteLaxMode,
+	}
+
+	if session.destroyed || len(session.Values) == 0 {
+		if !session.IsNew() || session.destroyed {
+			err := store.Delete(ctx, session)
+			if err != nil {
+				return err
+			}
+
+			cookie.MaxAge = -1
+			ctx.SetCookie(cookie)
+		}
+
+		return nil
+	}
+
+	value, err := store.Save(ctx, session)
+	if err != nil {
+		return err
+	}
+
+	_, absolute := getSessionTimeouts(app)
+	cookie.Value = SignValue(app.GetSecret(), value)
+	cookie.Expires = session.CreatedAt.Add(absolute)
+	ctx.SetCookie(cookie)
+
+	session.store = store
+
+	return nil
+}
+
+// Middleware that loads the request session and save it before the response is written
+func sessionMiddleware(app App) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.(*RequestContext)
+			ctx.Session = loadSession(ctx)
+
+			save := func() {
+				err := saveSession(ctx, ctx.Session)
+				if err != nil {
+					logrus.WithFields(logrus.Fields{
+						"error": fmt.Sprintf("%+v\n", err),
+						"path":  c.Path(),
+					}).Error("bolo.sessionMiddleware error on save session")
+				}
+			}
+
+			c.Response().Before(save)
+
+			err := next(ctx)
+			// handlers that don't write a response body
+			if err == nil && !c.Response().Committed {
+				save()
+			}
+
+			return err
+		}
+	}
+}
+
+func newSessionStoreFromConfiguration(app App) SessionStore {
+	switch app.GetConfiguration().GetF("SESSION_STORE", "cookie") {
+	case "memory":
+		return NewMemorySessionStore()
+	case "db":
+		return &DBSessionStore{}
+	default:
+		return &CookieSessionStore{}
+	}
+}
@@ -0,0 +1,191 @@
+package bolo
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// CookieSessionStore - Store all session data in the signed session cookie. Cookies have a size limit of 4KB
+type CookieSessionStore struct{}
+
+func (s *CookieSessionStore) Load(ctx *RequestContext, value string) (*Session, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, nil
+	}
+
+	session := Session{}
+	err = json.Unmarshal(data, &session)
+	if err != nil {
+		return nil, nil
+	}
+
+	if session.Values == nil {
+		session.Values = map[string]interface{}{}
+	}
+
+	return &session, nil
+}
+
+func (s *CookieSessionStore) Save(ctx *RequestContext, session *Session) (string, error) {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return "", err
+	}
+
+	value := base64.RawURLEncoding.EncodeToString(data)
+	if len(value) > 3800 {
+		logrus.WithFields(logrus.Fields{
+			"size": len(value),
+		}).Warn("bolo.CookieSessionStore.Save session cookie is too big, use the db or memory session store")
+	}
+
+	return value, nil
+}
+
+func (s *CookieSessionStore) Delete(ctx *RequestContext, session *Session) error {
+	return nil
+}
+
+// MemorySessionStore - Store sessions in the app process memory, only for development and single instance apps
+type MemorySessionStore struct {
+	mu       sync.RWMutex
+	sessions map[string][]byte
+}
+
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{
+		sessions: map[string][]byte{},
+	}
+}
+
+func (s *MemorySessionStore) Load(ctx *RequestContext, value string) (*Session, error) {
+	s.mu.RLock()
+	data, ok := s.sessions[value]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, nil
+	}
+
+	session := Session{}
+	err := json.Unmarshal(data, &session)
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (s *MemorySessionStore) Save(ctx *RequestContext, session *Session) (string, error) {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	s.sessions[session.ID] = data
+	s.mu.Unlock()
+
+	return session.ID, nil
+}
+
+func (s *MemorySessionStore) Delete(ctx *RequestContext, session *Session) error {
+	s.mu.Lock()
+	delete(s.sessions, session.ID)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// DeleteExpired - Remove sessions not accessed after the lastAccessBefore date
+func (s *MemorySessionStore) DeleteExpired(lastAccessBefore time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, data := range s.sessions {
+		session := Session{}
+		err := json.Unmarshal(data, &session)
+		if err != nil || session.LastAccessAt.Before(lastAccessBefore) {
+			delete(s.sessions, id)
+		}
+	}
+
+	return nil
+}
+
+// DBSessionStore - Store sessions in the bolo_sessions table of the default database
+type DBSessionStore struct{}
+
+func (s *DBSessionStore) Load(ctx *RequestContext, value string) (*Session, error) {
+	record := SessionModel{}
+	err := ctx.App.GetDB().Where("id = ?", value).First(&record).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	session := Session{
+		ID:           record.ID,
+		Values:       map[string]interface{}{},
+		CreatedAt:    record.CreatedAt,
+		LastAccessAt: record.LastAccessAt,
+	}
+
+	err = json.Unmarshal([]byte(record.Data), &session.Values)
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (s *DBSessionStore) Save(ctx *RequestContext, session *Session) (string, error) {
+	data, err := json.Marshal(session.Values)
+	if err != nil {
+		return "", err
+	}
+
+	record := SessionModel{
+		ID:           session.ID,
+		Data:         string(data),
+		CreatedAt:    session.CreatedAt,
+		LastAccessAt: session.LastAccessAt,
+	}
+
+	err = ctx.App.GetDB().Save(&record).Error
+	if err != nil {
+		return "", err
+	}
+
+	return session.ID, nil
+}
+
+func (s *DBSessionStore) Delete(ctx *RequestContext, session *Session) error {
+	return ctx.App.GetDB().Where("id = ?", session.ID).Delete(&SessionModel{}).Error
+}
+
+// DeleteExpired - Remove sessions not accessed after the lastAccessBefore date
+func (s *DBSessionStore) DeleteExpired(app App, lastAccessBefore time.Time) error {
+	return app.GetDB().Where("last_access_at < ?", lastAccessBefore).Delete(&SessionModel{}).Error
+}
+
+type SessionModel struct {
+	ID           string    `gorm:"column:id;primaryKey;type:varchar(64)"`
+	Data         string    `gorm:"column:data;type:text"`
+	CreatedAt    time.Time `gorm:"column:created_at;not null"`
+	LastAccessAt time.Time `gorm:"column:last_access_at;not null;index"`
+}
+
+func (m *SessionModel) TableName() string {
+	return "bolo_sessions"
+}
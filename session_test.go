@@ -0,0 +1,125 @@
+package bolo_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-bolo/bolo"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSession(t *testing.T) {
+	app := GetTestApp()
+	app.SetSessionStore(bolo.NewMemorySessionStore())
+	app.SetUserLoader(bolo.NewUserLoaderFromModel(func() bolo.UserInterface {
+		return &UserModel{}
+	}))
+
+	err := app.Bootstrap()
+	assert.Nil(t, err)
+
+	router := app.GetRouter()
+	router.POST("/login", func(c echo.Context) error {
+		ctx := c.(*bolo.RequestContext)
+		ctx.Session.Set("visitorName", "Alberto")
+		return ctx.Login(&UserModel{ID: "10", Active: true})
+	})
+	router.GET("/me", func(c echo.Context) error {
+		ctx := c.(*bolo.RequestContext)
+		if !ctx.IsAuthenticated {
+			return c.String(http.StatusOK, "anonymous")
+		}
+
+		return c.String(http.StatusOK, ctx.AuthenticatedUser.GetID()+":"+ctx.Session.GetString("visitorName"))
+	})
+	router.POST("/logout", func(c echo.Context) error {
+		return c.(*bolo.RequestContext).Logout()
+	})
+
+	serve := func(method, url string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, nil)
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("should start as anonymous", func(t *testing.T) {
+		rec := serve(http.MethodGet, "/me", nil)
+		assert.Equal(t, "anonymous", rec.Body.String())
+		assert.Empty(t, rec.Result().Cookies())
+	})
+
+	fixedCookie := &http.Cookie{Name: "bolo_session", Value: bolo.SignValue(app.GetSecret(), "attacker-session-id")}
+
+	rec := serve(http.MethodPost, "/login", []*http.Cookie{fixedCookie})
+	cookies := rec.Result().Cookies()
+
+	t.Run("should login and set a new session cookie", func(t *testing.T) {
+		assert.Len(t, cookies, 1)
+		assert.NotEqual(t, fixedCookie.Value, cookies[0].Value)
+		assert.True(t, cookies[0].HttpOnly)
+	})
+
+	t.Run("should load the session in next requests", func(t *testing.T) {
+		rec := serve(http.MethodGet, "/me", cookies)
+		assert.Equal(t, "10:Alberto", rec.Body.String())
+	})
+
+	t.Run("should ignore cookies with invalid signature", func(t *testing.T) {
+		rec := serve(http.MethodGet, "/me", []*http.Cookie{{Name: "bolo_session", Value: cookies[0].Value + "x"}})
+		assert.Equal(t, "anonymous", rec.Body.String())
+	})
+
+	t.Run("should destroy the session on logout", func(t *testing.T) {
+		rec := serve(http.MethodPost, "/logout", cookies)
+		assert.Equal(t, -1, rec.Result().Cookies()[0].MaxAge)
+
+		rec = serve(http.MethodGet, "/me", cookies)
+		assert.Equal(t, "anonymous", rec.Body.String())
+	})
+}
+
+func TestFlashMessages(t *testing.T) {
+	app := GetTestApp()
+	app.SetSessionStore(bolo.NewMemorySessionStore())
+
+	err := app.Bootstrap()
+	assert.Nil(t, err)
+
+	router := app.GetRouter()
+	router.POST("/contact", func(c echo.Context) error {
+		ctx := c.(*bolo.RequestContext)
+		ctx.AddResponseMessage(&bolo.ResponseMessage{Message: "Message sent", Type: "success"})
+		return ctx.Redirect(http.StatusSeeOther, "/contact")
+	})
+	router.GET("/contact", func(c echo.Context) error {
+		ctx := c.(*bolo.RequestContext)
+		return c.JSON(http.StatusOK, ctx.GetResponseMessages())
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/contact", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusSeeOther, rec.Code)
+
+	cookies := rec.Result().Cookies()
+	assert.Len(t, cookies, 1)
+
+	req = httptest.NewRequest(http.MethodGet, "/contact", nil)
+	req.AddCookie(cookies[0])
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.JSONEq(t, `[{"message":"Message sent","type":"success"}]`, rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/contact", nil)
+	req.AddCookie(cookies[0])
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.JSONEq(t, `null`, rec.Body.String())
+}
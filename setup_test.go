@@ -2,6 +2,7 @@ package bolo_test
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -33,3 +34,19 @@ func GetTestApp() bolo.App {
 
 	return app
 }
+
+// Write the theme templates in one temporary TEMPLATE_FOLDER, the names are relative to the theme folder, ex: site/404.html
+func setTestTemplates(t *testing.T, templates map[string]string) {
+	dir := t.TempDir()
+	for name, content := range templates {
+		file := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Setenv("TEMPLATE_FOLDER", dir)
+}
@@ -0,0 +1,61 @@
+package bolo
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// SignValue - Sign one value with HMAC-SHA256, the result format is: [value].[signature]
+func SignValue(secret []byte, value string) string {
+	return value + "." + signatureOf(secret, value)
+}
+
+// VerifySignedValue - Check one value signed with SignValue and return the original value
+func VerifySignedValue(secret []byte, signed string) (string, error) {
+	i := strings.LastIndex(signed, ".")
+	if i < 0 {
+		return "", ErrInvalidSignature
+	}
+
+	value := signed[:i]
+	if !hmac.Equal([]byte(signed[i+1:]), []byte(signatureOf(secret, value))) {
+		return "", ErrInvalidSignature
+	}
+
+	return value, nil
+}
+
+func signatureOf(secret []byte, value string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// RandomToken - Generate a random url safe token with n random bytes
+func RandomToken(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(errors.Wrap(err, "bolo.RandomToken error on read random bytes"))
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func loadAppSecret(app App) []byte {
+	secret := app.GetConfiguration().Get("APP_SECRET")
+	if secret != "" {
+		return []byte(secret)
+	}
+
+	logrus.Warn("bolo.loadAppSecret APP_SECRET is empty, using a random secret. Signed cookies and tokens will be invalid after restart")
+
+	return []byte(RandomToken(32))
+}
@@ -0,0 +1,165 @@
+package bolo
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-bolo/bolo/tracing"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Create the app tracer from configuration, TRACING_EXPORTER: stdout, otlp or empty to disable tracing
+func newTracerFromConfiguration(app App) *tracing.Tracer {
+	cfg := app.GetConfiguration()
+
+	serviceName := cfg.GetF("TRACING_SERVICE_NAME", cfg.GetF("SITE_NAME", "bolo"))
+
+	var exporter tracing.Exporter
+
+	switch cfg.GetF("TRACING_EXPORTER", "") {
+	case "stdout":
+		exporter = tracing.NewStdoutExporter()
+	case "otlp":
+		otlp := tracing.NewOTLPExporter(cfg.GetF("TRACING_OTLP_ENDPOINT", "http://localhost:4318/v1/traces"), serviceName)
+		if authorization := cfg.GetF("TRACING_OTLP_AUTHORIZATION", ""); authorization != "" {
+			otlp.Headers["Authorization"] = authorization
+		}
+		exporter = otlp
+	case "":
+	default:
+		logrus.WithFields(logrus.Fields{
+			"exporter": cfg.GetF("TRACING_EXPORTER", ""),
+		}).Warn("bolo.newTracerFromConfiguration invalid TRACING_EXPORTER, options: stdout or otlp")
+	}
+
+	ratio, err := strconv.ParseFloat(cfg.GetF("TRACING_SAMPLE_RATIO", "1"), 64)
+	if err != nil {
+		ratio = 1
+	}
+
+	return tracing.NewTracer(&tracing.TracerOptions{
+		ServiceName: serviceName,
+		Exporter:    exporter,
+		SampleRatio: ratio,
+	})
+}
+
+// Middleware that creates one server span for each request, child of the received traceparent
+func tracingMiddleware(app App) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			tracer := app.GetTracer()
+			if !tracer.IsEnabled() {
+				return next(c)
+			}
+
+			req := c.Request()
+
+			route := c.Path()
+			if route == "" {
+				route = metricsNotFoundRoute
+			}
+
+			ctx := tracing.Extract(req.Context(), req.Header)
+			ctx, span := tracer.Start(ctx, req.Method+" "+route, tracing.SpanKindServer)
+			defer span.End()
+
+			c.SetRequest(req.WithContext(ctx))
+
+			span.SetAttribute("http.method", req.Method)
+			span.SetAttribute("http.route", route)
+			span.SetAttribute("http.target", req.URL.Path)
+			span.SetAttribute("http.client_ip", c.RealIP())
+			if id, ok := c.Get(RequestIDKey).(string); ok {
+				span.SetAttribute("http.request_id", id)
+			}
+
+			// run the error handler now to record the final response status
+			err := handleErrorOnce(c.(*RequestContext), next(c))
+			if err != nil {
+				span.SetError(err)
+			}
+
+			status := c.Response().Status
+			span.SetAttribute("http.status_code", status)
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(tracing.StatusError, http.StatusText(status))
+			}
+
+			return err
+		}
+	}
+}
+
+// Register gorm callbacks that create child spans for queries that run with one traced context, see db.WithContext
+func registerDBTracingCallbacks(app App, dbName string, db *gorm.DB) error {
+	before := func(operation string) func(tx *gorm.DB) {
+		return func(tx *gorm.DB) {
+			_, span := tracing.StartSpan(tx.Statement.Context, "db."+operation, tracing.SpanKindClient)
+			if span == nil {
+				return
+			}
+
+			span.SetAttribute("db.name", dbName)
+			span.SetAttribute("db.system", tx.Dialector.Name())
+			span.SetAttribute("db.operation", operation)
+			tx.InstanceSet("bolo:tracingSpan", span)
+		}
+	}
+
+	after := func(tx *gorm.DB) {
+		v, ok := tx.InstanceGet("bolo:tracingSpan")
+		if !ok {
+			return
+		}
+
+		span := v.(*tracing.Span)
+		span.SetAttribute("db.sql.table", tx.Statement.Table)
+		span.SetAttribute("db.statement", tx.Statement.SQL.String())
+		span.SetAttribute("db.rows_affected", tx.RowsAffected)
+		if tx.Error != nil && tx.Error != gorm.ErrRecordNotFound {
+			span.SetError(tx.Error)
+		}
+		span.End()
+	}
+
+	cb := db.Callback()
+
+	if err := cb.Create().Before("gorm:create").Register("bolo:tracing_before_create", before("create")); err != nil {
+		return err
+	}
+	if err := cb.Create().After("gorm:create").Register("bolo:tracing_after_create", after); err != nil {
+		return err
+	}
+	if err := cb.Query().Before("gorm:query").Register("bolo:tracing_before_query", before("query")); err != nil {
+		return err
+	}
+	if err := cb.Query().After("gorm:query").Register("bolo:tracing_after_query", after); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("bolo:tracing_before_update", before("update")); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Register("bolo:tracing_after_update", after); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("bolo:tracing_before_delete", before("delete")); err != nil {
+		return err
+	}
+	if err := cb.Delete().After("gorm:delete").Register("bolo:tracing_after_delete", after); err != nil {
+		return err
+	}
+	if err := cb.Row().Before("gorm:row").Register("bolo:tracing_before_row", before("row")); err != nil {
+		return err
+	}
+	if err := cb.Row().After("gorm:row").Register("bolo:tracing_after_row", after); err != nil {
+		return err
+	}
+	if err := cb.Raw().Before("gorm:raw").Register("bolo:tracing_before_raw", before("raw")); err != nil {
+		return err
+	}
+
+	return cb.Raw().After("gorm:raw").Register("bolo:tracing_after_raw", after)
+}
@@ -0,0 +1,440 @@
+// Package tracing implements spans with W3C trace context propagation, exported to stdout or one OTLP collector
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// W3C trace context header
+const TraceParentHeader = "traceparent"
+
+type TraceID [16]byte
+
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+type SpanID [8]byte
+
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+// SpanContext - Span identification propagated between services
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+	// Received from other service in one traceparent header
+	Remote bool
+}
+
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// TraceParent - Format the span context as one W3C traceparent header value
+func (sc SpanContext) TraceParent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+
+	return "00-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-" + flags
+}
+
+// ParseTraceParent - Parse one W3C traceparent header value
+func ParseTraceParent(value string) (SpanContext, bool) {
+	sc := SpanContext{}
+
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return sc, false
+	}
+	// version 00 has exactly 4 fields, future versions can add more
+	if parts[0] == "00" && len(parts) != 4 {
+		return sc, false
+	}
+
+	if _, err := hex.Decode(sc.TraceID[:], []byte(parts[1])); err != nil {
+		return sc, false
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(parts[2])); err != nil {
+		return sc, false
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return sc, false
+	}
+
+	sc.Sampled = flags[0]&1 == 1
+	sc.Remote = true
+
+	return sc, sc.IsValid()
+}
+
+type SpanKind int
+
+// Values used in the OTLP format
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+type StatusCode int
+
+const (
+	StatusUnset StatusCode = 0
+	StatusOK    StatusCode = 1
+	StatusError StatusCode = 2
+)
+
+// Span - One operation of one trace. Span methods are safe to use with nil spans
+type Span struct {
+	mu sync.Mutex
+
+	Name          string
+	Kind          SpanKind
+	SpanContext   SpanContext
+	ParentSpanID  SpanID
+	StartTime     time.Time
+	EndTime       time.Time
+	Attributes    map[string]interface{}
+	Status        StatusCode
+	StatusMessage string
+
+	tracer *Tracer
+	ended  bool
+}
+
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.Attributes[key] = value
+	s.mu.Unlock()
+}
+
+// SetError - Mark the span as failed
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.Status = StatusError
+	s.StatusMessage = err.Error()
+	s.mu.Unlock()
+}
+
+func (s *Span) SetStatus(code StatusCode, message string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.Status = code
+	s.StatusMessage = message
+	s.mu.Unlock()
+}
+
+// End - Finish the span and send it to the tracer exporter
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.EndTime = time.Now()
+	s.mu.Unlock()
+
+	if s.SpanContext.Sampled {
+		s.tracer.enqueue(s)
+	}
+}
+
+// Exporter - Send finished spans to one backend
+type Exporter interface {
+	Export(ctx context.Context, spans []*Span) error
+	Shutdown(ctx context.Context) error
+}
+
+type TracerOptions struct {
+	ServiceName string
+	// Spans are only created when one exporter is set
+	Exporter Exporter
+	// Ratio of new traces sampled, from 0 to 1. Traces received with traceparent keep the caller decision
+	SampleRatio float64
+	// Max spans in one export, default 512
+	BatchSize int
+	// Interval between exports, default 5s
+	BatchTimeout time.Duration
+}
+
+// Tracer - Create spans and export them in batches
+type Tracer struct {
+	opts *TracerOptions
+
+	mu      sync.Mutex
+	queue   []*Span
+	flushCh chan struct{}
+	done    chan struct{}
+	stopped sync.Once
+	wg      sync.WaitGroup
+}
+
+func NewTracer(opts *TracerOptions) *Tracer {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 512
+	}
+	if opts.BatchTimeout <= 0 {
+		opts.BatchTimeout = 5 * time.Second
+	}
+
+	t := &Tracer{
+		opts:    opts,
+		flushCh: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+
+	if opts.Exporter != nil {
+		t.wg.Add(1)
+		go t.run()
+	}
+
+	return t
+}
+
+// IsEnabled - Check if the tracer records spans
+func (t *Tracer) IsEnabled() bool {
+	return t != nil && t.opts.Exporter != nil
+}
+
+func (t *Tracer) GetServiceName() string {
+	return t.opts.ServiceName
+}
+
+// Start - Start one span, child of the span or remote span context in ctx.
+// Returns a nil span if tracing is disabled
+func (t *Tracer) Start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	if !t.IsEnabled() {
+		return ctx, nil
+	}
+
+	span := &Span{
+		Name:       name,
+		Kind:       kind,
+		StartTime:  time.Now(),
+		Attributes: map[string]interface{}{},
+		tracer:     t,
+	}
+
+	parent := SpanContextFromContext(ctx)
+	if parent.IsValid() {
+		span.SpanContext.TraceID = parent.TraceID
+		span.SpanContext.Sampled = parent.Sampled
+		span.ParentSpanID = parent.SpanID
+	} else {
+		span.SpanContext.TraceID = newTraceID()
+		span.SpanContext.Sampled = t.shouldSample(span.SpanContext.TraceID)
+	}
+	span.SpanContext.SpanID = newSpanID()
+
+	return ContextWithSpan(ctx, span), span
+}
+
+// Sample by trace id, so all services with the same ratio take the same decision
+func (t *Tracer) shouldSample(id TraceID) bool {
+	ratio := t.opts.SampleRatio
+	if ratio >= 1 {
+		return true
+	}
+	if ratio <= 0 {
+		return false
+	}
+
+	return binary.BigEndian.Uint64(id[8:])>>1 < uint64(ratio*(1<<63))
+}
+
+func (t *Tracer) enqueue(s *Span) {
+	t.mu.Lock()
+	// drop spans if the exporter can't keep up
+	if len(t.queue) < t.opts.BatchSize*4 {
+		t.queue = append(t.queue, s)
+	}
+	full := len(t.queue) >= t.opts.BatchSize
+	t.mu.Unlock()
+
+	if full {
+		select {
+		case t.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (t *Tracer) run() {
+	defer t.wg.Done()
+
+	ticker := time.NewTicker(t.opts.BatchTimeout)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			t.Flush(context.Background())
+		case <-t.flushCh:
+			t.Flush(context.Background())
+		case <-t.done:
+			return
+		}
+	}
+}
+
+// Flush - Export all finished spans now
+func (t *Tracer) Flush(ctx context.Context) error {
+	if !t.IsEnabled() {
+		return nil
+	}
+
+	t.mu.Lock()
+	spans := t.queue
+	t.queue = nil
+	t.mu.Unlock()
+
+	for len(spans) > 0 {
+		size := t.opts.BatchSize
+		if size > len(spans) {
+			size = len(spans)
+		}
+
+		err := t.opts.Exporter.Export(ctx, spans[:size])
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"error": err.Error(),
+				"spans": size,
+			}).Warn("tracing.Tracer.Flush error on export spans")
+		}
+
+		spans = spans[size:]
+	}
+
+	return nil
+}
+
+// Shutdown - Export pending spans and stop the exporter
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if !t.IsEnabled() {
+		return nil
+	}
+
+	t.stopped.Do(func() {
+		close(t.done)
+	})
+	t.wg.Wait()
+
+	t.Flush(ctx)
+
+	return t.opts.Exporter.Shutdown(ctx)
+}
+
+type spanContextKey struct{}
+type remoteSpanContextKey struct{}
+
+// ContextWithSpan - Set the current span in ctx
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, span)
+}
+
+// SpanFromContext - Get the current span, nil if ctx has no span
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+
+	span, _ := ctx.Value(spanContextKey{}).(*Span)
+	return span
+}
+
+// SpanContextFromContext - Get the current span context, from the current span or one remote parent
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if span := SpanFromContext(ctx); span != nil {
+		return span.SpanContext
+	}
+
+	if ctx != nil {
+		if sc, ok := ctx.Value(remoteSpanContextKey{}).(SpanContext); ok {
+			return sc
+		}
+	}
+
+	return SpanContext{}
+}
+
+// StartSpan - Start one child span with the tracer of the current span in ctx.
+// Returns a nil span if ctx has no span, used in libraries that don't have access to the app tracer
+func StartSpan(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	parent := SpanFromContext(ctx)
+	if parent == nil {
+		return ctx, nil
+	}
+
+	return parent.tracer.Start(ctx, name, kind)
+}
+
+// Extract - Read the remote span context from one traceparent header
+func Extract(ctx context.Context, header http.Header) context.Context {
+	sc, ok := ParseTraceParent(header.Get(TraceParentHeader))
+	if !ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, remoteSpanContextKey{}, sc)
+}
+
+// Inject - Set the traceparent header with the current span context
+func Inject(ctx context.Context, header http.Header) {
+	sc := SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+
+	header.Set(TraceParentHeader, sc.TraceParent())
+}
+
+func newTraceID() TraceID {
+	id := TraceID{}
+	rand.Read(id[:])
+	return id
+}
+
+func newSpanID() SpanID {
+	id := SpanID{}
+	rand.Read(id[:])
+	return id
+}
@@ -0,0 +1,99 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/go-bolo/bolo/tracing"
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryExporter struct {
+	mu    sync.Mutex
+	spans []*tracing.Span
+}
+
+func (e *memoryExporter) Export(ctx context.Context, spans []*tracing.Span) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *memoryExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func TestParseTraceParent(t *testing.T) {
+	tests := []struct {
+		value   string
+		valid   bool
+		sampled bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true, true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", true, false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false, false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", false, false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false, false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e473z-00f067aa0ba902b7-01", false, false},
+		{"invalid", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			sc, ok := tracing.ParseTraceParent(tt.value)
+			assert.Equal(t, tt.valid, ok)
+			if tt.valid {
+				assert.Equal(t, tt.sampled, sc.Sampled)
+				assert.Equal(t, tt.value, sc.TraceParent())
+			}
+		})
+	}
+}
+
+func TestTracer_Start(t *testing.T) {
+	exporter := &memoryExporter{}
+	tracer := tracing.NewTracer(&tracing.TracerOptions{
+		ServiceName: "test",
+		Exporter:    exporter,
+		SampleRatio: 1,
+	})
+
+	header := http.Header{}
+	header.Set(tracing.TraceParentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	ctx := tracing.Extract(context.Background(), header)
+	ctx, server := tracer.Start(ctx, "GET /", tracing.SpanKindServer)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext.TraceID.String())
+	assert.Equal(t, "00f067aa0ba902b7", server.ParentSpanID.String())
+
+	childCtx, child := tracing.StartSpan(ctx, "db.query", tracing.SpanKindClient)
+	assert.Equal(t, server.SpanContext.TraceID, child.SpanContext.TraceID)
+	assert.Equal(t, server.SpanContext.SpanID, child.ParentSpanID)
+
+	outgoing := http.Header{}
+	tracing.Inject(childCtx, outgoing)
+	assert.Equal(t, child.SpanContext.TraceParent(), outgoing.Get(tracing.TraceParentHeader))
+
+	child.End()
+	server.End()
+	server.End()
+
+	err := tracer.Shutdown(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(exporter.spans))
+
+	t.Run("should not create spans without one parent span", func(t *testing.T) {
+		_, span := tracing.StartSpan(context.Background(), "orphan", tracing.SpanKindInternal)
+		assert.Nil(t, span)
+		span.SetAttribute("key", "value")
+		span.End()
+	})
+
+	t.Run("should not record spans without exporter", func(t *testing.T) {
+		disabled := tracing.NewTracer(&tracing.TracerOptions{})
+		_, span := disabled.Start(context.Background(), "GET /", tracing.SpanKindServer)
+		assert.Nil(t, span)
+	})
+}
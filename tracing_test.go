@@ -0,0 +1,125 @@
+package bolo_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-bolo/bolo"
+	"github.com/go-bolo/bolo/http_client"
+	"github.com/go-bolo/bolo/tracing"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type memorySpanExporter struct {
+	mu    sync.Mutex
+	spans []*tracing.Span
+}
+
+func (e *memorySpanExporter) Export(ctx context.Context, spans []*tracing.Span) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *memorySpanExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func TestTracingMiddleware(t *testing.T) {
+	app := GetTestApp()
+	err := app.Bootstrap()
+	assert.Nil(t, err)
+
+	exporter := &memorySpanExporter{}
+	app.SetTracer(tracing.NewTracer(&tracing.TracerOptions{Exporter: exporter, SampleRatio: 1}))
+
+	var receivedTraceParent string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedTraceParent = r.Header.Get(tracing.TraceParentHeader)
+	}))
+	defer upstream.Close()
+
+	var logTraceID interface{}
+	app.GetRouter().GET("/traced/:id", func(c echo.Context) error {
+		ctx := c.(*bolo.RequestContext)
+		logTraceID = ctx.Log().Data["traceID"]
+
+		var count int64
+		err := app.GetDB().WithContext(ctx.Request().Context()).Raw("SELECT 1").Count(&count).Error
+		assert.Nil(t, err)
+
+		req, _ := http.NewRequestWithContext(ctx.Request().Context(), http.MethodGet, upstream.URL, nil)
+		res, err := http_client.HttpClient.Do(req)
+		assert.Nil(t, err)
+		res.Body.Close()
+
+		return c.String(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/traced/1", nil)
+	req.Header.Set(tracing.TraceParentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	app.GetRouter().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	err = app.Close()
+	assert.Nil(t, err)
+
+	spans := map[string]*tracing.Span{}
+	for _, s := range exporter.spans {
+		spans[s.Name] = s
+	}
+
+	server := spans["GET /traced/:id"]
+	if assert.NotNil(t, server) {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext.TraceID.String())
+		assert.Equal(t, "00f067aa0ba902b7", server.ParentSpanID.String())
+		assert.Equal(t, 200, server.Attributes["http.status_code"])
+		assert.Equal(t, server.SpanContext.TraceID.String(), logTraceID)
+	}
+
+	db := spans["db.query"]
+	if assert.NotNil(t, db) && server != nil {
+		assert.Equal(t, server.SpanContext.SpanID, db.ParentSpanID)
+	}
+
+	client := spans["HTTP GET"]
+	if assert.NotNil(t, client) && server != nil {
+		assert.Equal(t, server.SpanContext.SpanID, client.ParentSpanID)
+		assert.Equal(t, client.SpanContext.TraceParent(), receivedTraceParent)
+	}
+}
+
+func TestTracingMiddleware_Error(t *testing.T) {
+	app := GetTestApp()
+	assert.Nil(t, app.Bootstrap())
+
+	exporter := &memorySpanExporter{}
+	app.SetTracer(tracing.NewTracer(&tracing.TracerOptions{Exporter: exporter, SampleRatio: 1}))
+
+	app.GetRouter().GET("/traced-error", func(c echo.Context) error {
+		return &bolo.HTTPError{Code: http.StatusBadRequest, Message: "invalid filter"}
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/traced-error", nil)
+	req.Header.Set(echo.HeaderAccept, "application/json")
+	rec := httptest.NewRecorder()
+	app.GetRouter().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, 1, strings.Count(rec.Body.String(), "invalid filter"), "should write the error response once")
+
+	assert.Nil(t, app.Close())
+
+	if assert.Len(t, exporter.spans, 1) {
+		span := exporter.spans[0]
+		assert.Equal(t, tracing.StatusError, span.Status)
+		assert.Contains(t, span.StatusMessage, "invalid filter")
+		assert.Equal(t, http.StatusBadRequest, span.Attributes["http.status_code"])
+	}
+}
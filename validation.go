@@ -0,0 +1,142 @@
+package bolo
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	"github.com/go-playground/locales/pt"
+	"github.com/go-playground/locales/pt_BR"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	es_translations "github.com/go-playground/validator/v10/translations/es"
+	pt_translations "github.com/go-playground/validator/v10/translations/pt"
+	pt_BR_translations "github.com/go-playground/validator/v10/translations/pt_BR"
+)
+
+// Create the app validator, with the JSON names in the error fields
+func newValidator() *validator.Validate {
+	v := validator.New()
+
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		// fields not encoded in JSON keep the struct field name, the validator skips fields named "-"
+		if name := getJSONName(field); name != "" {
+			return name
+		}
+
+		return field.Name
+	})
+
+	return v
+}
+
+// Create the translator of the validation messages with the validator default translations
+func newValidationTranslator(v *validator.Validate) (*ut.UniversalTranslator, error) {
+	translator := ut.New(en.New(), en.New(), es.New(), pt.New(), pt_BR.New())
+
+	translations := map[string]func(v *validator.Validate, trans ut.Translator) error{
+		"en":    en_translations.RegisterDefaultTranslations,
+		"es":    es_translations.RegisterDefaultTranslations,
+		"pt":    pt_translations.RegisterDefaultTranslations,
+		"pt_BR": pt_BR_translations.RegisterDefaultTranslations,
+	}
+
+	for locale, register := range translations {
+		trans, _ := translator.GetTranslator(locale)
+		if err := register(v, trans); err != nil {
+			return nil, err
+		}
+
+		if err := registerDocumentTranslations(v, trans, locale); err != nil {
+			return nil, err
+		}
+	}
+
+	return translator, nil
+}
+
+// messages of the documents package validation tags, {1} is the document name
+var documentValidationMessages = map[string]string{
+	"en":    "{0} must be a valid {1}",
+	"es":    "{0} debe ser un {1} válido",
+	"pt":    "{0} deve ser um {1} válido",
+	"pt_BR": "{0} deve ser um {1} válido",
+}
+
+// Register the translations of the cpf, cnpj and cep validation tags
+func registerDocumentTranslations(v *validator.Validate, trans ut.Translator, locale string) error {
+	for _, tag := range []string{"cpf", "cnpj", "cep"} {
+		err := v.RegisterTranslation(tag, trans, func(ut ut.Translator) error {
+			return ut.Add(tag, documentValidationMessages[locale], false)
+		}, func(ut ut.Translator, fe validator.FieldError) string {
+			msg, err := ut.T(fe.Tag(), fe.Field(), strings.ToUpper(fe.Tag()))
+			if err != nil {
+				return fe.Error()
+			}
+			return msg
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Get the validation messages translator for the request language
+func getValidationTranslator(ctx *RequestContext) ut.Translator {
+	translator := ctx.App.GetValidationTranslator()
+	if translator == nil {
+		return nil
+	}
+
+	locales := []string{}
+	for _, candidate := range append(getLocaleCandidates(ctx), ctx.GetLocale()) {
+		// universal translator format, ex: pt_BR
+		base, region, hasRegion := strings.Cut(strings.ReplaceAll(candidate, "-", "_"), "_")
+		if hasRegion {
+			locales = append(locales, strings.ToLower(base)+"_"+strings.ToUpper(region))
+		}
+		locales = append(locales, strings.ToLower(base))
+	}
+
+	trans, _ := translator.FindTranslator(locales...)
+	return trans
+}
+
+// Get the field path of one validation error with the JSON names, ex: url.tags[0].name
+func getValidationFieldPath(fe validator.FieldError) string {
+	_, path, ok := strings.Cut(fe.Namespace(), ".")
+	if !ok {
+		return fe.Field()
+	}
+
+	return path
+}
+
+// Convert the validator errors to the response errors, with the messages in the request language
+func parseValidationErrors(ctx *RequestContext, ve validator.ValidationErrors) []*ValidationFieldError {
+	trans := getValidationTranslator(ctx)
+	list := []*ValidationFieldError{}
+
+	for _, fe := range ve {
+		el := ValidationFieldError{
+			Field: getValidationFieldPath(fe),
+			Tag:   fe.Tag(),
+			Param: fe.Param(),
+			Value: fe.Value(),
+		}
+
+		if trans != nil {
+			el.Message = fe.Translate(trans)
+		} else {
+			el.Message = fe.Error()
+		}
+
+		list = append(list, &el)
+	}
+
+	return list
+}
@@ -0,0 +1,146 @@
+package bolo_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-bolo/bolo"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type validationTestItem struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type validationTestBody struct {
+	Title    string                `json:"title" validate:"required"`
+	Email    string                `json:"email" validate:"omitempty,email"`
+	Tags     []string              `json:"tags" validate:"max=2,dive,min=3"`
+	Items    []*validationTestItem `json:"items" validate:"dive"`
+	Password string                `json:"-" validate:"max=4"`
+}
+
+func TestValidationErrors(t *testing.T) {
+	app := GetTestApp()
+	assert.Nil(t, app.Bootstrap())
+
+	app.SetRoute(app.GetRouterGroup("main"), &bolo.Route{
+		Method:   http.MethodPost,
+		Path:     "validate",
+		SkipCSRF: true,
+		Action: func(c echo.Context) error {
+			body := validationTestBody{Password: "secret"}
+			if err := c.Bind(&body); err != nil {
+				return err
+			}
+
+			return c.Validate(&body)
+		},
+	})
+
+	serve := func(language, body string) []*bolo.ValidationFieldError {
+		req := httptest.NewRequest(http.MethodPost, "/validate", strings.NewReader(body))
+		req.Header.Set(echo.HeaderAccept, "application/json")
+		req.Header.Set(echo.HeaderContentType, "application/json")
+		if language != "" {
+			req.Header.Set("Accept-Language", language)
+		}
+		rec := httptest.NewRecorder()
+		app.GetRouter().ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+		resp := bolo.ValidationResponse{}
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return resp.Errors
+	}
+
+	t.Run("should return the JSON field paths and invalid values", func(t *testing.T) {
+		errs := serve("", `{"email":"invalid","tags":["ab","abcd"],"items":[{"name":"a"},{}]}`)
+
+		got := map[string]*bolo.ValidationFieldError{}
+		for _, e := range errs {
+			got[e.Field] = e
+		}
+
+		assert.Len(t, got, 5)
+		assert.Equal(t, "required", got["title"].Tag)
+		assert.Equal(t, "title is a required field", got["title"].Message)
+		assert.Equal(t, "invalid", got["email"].Value)
+		assert.Equal(t, "3", got["tags[0]"].Param)
+		assert.Equal(t, "ab", got["tags[0]"].Value)
+		assert.Equal(t, "required", got["items[1].name"].Tag)
+		assert.Equal(t, "max", got["Password"].Tag)
+	})
+
+	t.Run("should translate the messages to the request language", func(t *testing.T) {
+		tests := []struct {
+			language string
+			want     string
+		}{
+			{language: "pt-BR,pt;q=0.9,en;q=0.8", want: "title é um campo obrigatório"},
+			{language: "en;q=0.5, es", want: "title es un campo requerido"},
+			{language: "de", want: "title is a required field"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.language, func(t *testing.T) {
+				errs := serve(tt.language, `{"title":"","password":"1234"}`)
+				if assert.Len(t, errs, 2) {
+					assert.Equal(t, tt.want, errs[0].Message)
+				}
+			})
+		}
+	})
+}
+
+type documentValidationTestBody struct {
+	CPF  string `json:"cpf" validate:"cpf"`
+	CNPJ string `json:"cnpj" validate:"omitempty,cnpj"`
+	CEP  string `json:"cep" validate:"omitempty,cep"`
+}
+
+func TestDocumentValidations(t *testing.T) {
+	app := GetTestApp()
+	assert.Nil(t, app.Bootstrap())
+
+	translator := app.GetValidationTranslator()
+
+	tests := []struct {
+		name     string
+		body     documentValidationTestBody
+		locale   string
+		wantTags []string
+		want     string
+	}{
+		{name: "valid", body: documentValidationTestBody{CPF: "529.982.247-25", CNPJ: "12.ABC.345/01DE-35", CEP: "01310-100"}},
+		{name: "invalid cpf", body: documentValidationTestBody{CPF: "529.982.247-26"}, locale: "pt_BR", wantTags: []string{"cpf"}, want: "cpf deve ser um CPF válido"},
+		{name: "invalid cnpj and cep", body: documentValidationTestBody{CPF: "52998224725", CNPJ: "11222333000180", CEP: "0131"}, locale: "en", wantTags: []string{"cnpj", "cep"}, want: "cnpj must be a valid CNPJ"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := app.GetValidator().Struct(&tt.body)
+			if len(tt.wantTags) == 0 {
+				assert.Nil(t, err)
+				return
+			}
+
+			ve, ok := err.(validator.ValidationErrors)
+			if !assert.True(t, ok) {
+				return
+			}
+
+			tags := []string{}
+			for _, fe := range ve {
+				tags = append(tags, fe.Tag())
+			}
+			assert.Equal(t, tt.wantTags, tags)
+
+			trans, _ := translator.GetTranslator(tt.locale)
+			assert.Equal(t, tt.want, ve[0].Translate(trans))
+		})
+	}
+}
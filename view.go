@@ -28,6 +28,8 @@ type TemplateCTX struct {
 	Ctx         interface{}
 	Record      interface{}
 	Records     interface{}
+	// Request body validation errors, in the 400 template
+	ValidationErrors []*ValidationFieldError
 }
 
 type TemplateRenderer struct {